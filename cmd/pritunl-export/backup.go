@@ -0,0 +1,247 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/maulid7/terraform-provider-pritunl/pritunl"
+)
+
+// backupVersion is the version of the backup document format, it changes when a
+// document can no longer be restored by an older version of the tool
+const backupVersion = 1
+
+// backupDocument is a portable copy of the Pritunl configuration.
+//
+// The IDs are kept for reference only, restore matches the objects by name because a
+// new Pritunl assigns new IDs. Secrets such as the user PINs, OTP secrets, API
+// credentials and the single sign-on settings are left out.
+type backupDocument struct {
+	Version       int                    `json:"version"`
+	CreatedAt     time.Time              `json:"created_at"`
+	Organizations []backupOrganization   `json:"organizations"`
+	Servers       []backupServer         `json:"servers"`
+	Hosts         []pritunl.Host         `json:"hosts"`
+	Settings      map[string]interface{} `json:"settings"`
+}
+
+type backupOrganization struct {
+	ID      string           `json:"id"`
+	Name    string           `json:"name"`
+	AuthApi bool             `json:"auth_api"`
+	Users   []userAttributes `json:"users"`
+}
+
+type backupServer struct {
+	// the attributes include the status the server is restored to
+	Attributes    serverAttributes `json:"attributes"`
+	Routes        []pritunl.Route  `json:"routes"`
+	Organizations []string         `json:"organizations"`
+	Hosts         []string         `json:"hosts"`
+}
+
+// The API models encode themselves as request payloads, the attribute types encode the
+// fields as the API returns them
+
+type userAttributes pritunl.User
+
+type serverAttributes pritunl.Server
+
+var (
+	// user attributes restored by the restore command
+	userKeys = []string{
+		"name", "email", "groups", "disabled", "auth_type", "dns_servers", "dns_suffix", "dns_mapping",
+		"network_links", "port_forwarding", "mac_addresses", "client_to_client", "bypass_secondary", "yubico_id",
+	}
+
+	// server attributes that are read-only or restored separately
+	serverIgnoredKeys = []string{"id", "status", "wg"}
+
+	hostKeys = []string{
+		"name", "public_addr", "public_addr6", "routed_subnet6", "routed_subnet6_wg",
+		"local_addr", "local_addr6", "link_addr", "sync_address", "availability_group",
+	}
+
+	// the general settings, the single sign-on settings hold secrets so they aren't backed up
+	settingsKeys = []string{
+		"theme", "public_address", "public_address6", "routed_subnet6", "routed_subnet6_wg", "reverse_proxy",
+		"server_port", "acme_domain", "restrict_import", "client_reconnect", "pin_mode",
+		"email_from", "email_server", "email_username",
+	}
+)
+
+func runBackup(args []string, stdout io.Writer) error {
+	var connection connectionFlags
+	var out string
+
+	flags := flag.NewFlagSet("backup", flag.ContinueOnError)
+	connection.register(flags)
+	flags.StringVar(&out, "out", "-", "File to write the backup to, - writes to stdout")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	apiClient, err := connection.client()
+	if err != nil {
+		return err
+	}
+
+	document, err := backup(apiClient)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	if out == "-" {
+		_, err = stdout.Write(data)
+		return err
+	}
+
+	// the backup describes the users, so it isn't readable by others
+	return os.WriteFile(out, data, 0600)
+}
+
+// backup reads the configuration into a backup document
+func backup(apiClient pritunl.Client) (*backupDocument, error) {
+	inv, err := loadInventory(apiClient)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := apiClient.GetSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	document := &backupDocument{
+		Version:       backupVersion,
+		CreatedAt:     time.Now().UTC().Truncate(time.Second),
+		Organizations: make([]backupOrganization, 0, len(inv.Organizations)),
+		Servers:       make([]backupServer, 0, len(inv.Servers)),
+		Hosts:         inv.Hosts,
+		Settings:      attributes(settings, settingsKeys),
+	}
+
+	organizationNames := map[string]string{}
+	for _, organization := range inv.Organizations {
+		organizationNames[organization.ID] = organization.Name
+
+		users := make([]userAttributes, 0, len(inv.Users[organization.ID]))
+		for _, user := range inv.Users[organization.ID] {
+			user.Pin = nil
+			user.OtpSecret = ""
+			user.Status = false
+			users = append(users, userAttributes(user))
+		}
+
+		document.Organizations = append(document.Organizations, backupOrganization{
+			ID:      organization.ID,
+			Name:    organization.Name,
+			AuthApi: organization.AuthApi,
+			Users:   users,
+		})
+	}
+
+	hostnames := map[string]string{}
+	for _, host := range inv.Hosts {
+		hostnames[host.ID] = host.Hostname
+	}
+
+	for _, server := range inv.Servers {
+		backupServer := backupServer{
+			Attributes:    serverAttributes(server),
+			Routes:        inv.Routes[server.ID],
+			Organizations: make([]string, 0),
+			Hosts:         make([]string, 0),
+		}
+
+		for _, id := range inv.ServerOrganizations[server.ID] {
+			backupServer.Organizations = append(backupServer.Organizations, organizationNames[id])
+		}
+		for _, id := range inv.ServerHosts[server.ID] {
+			backupServer.Hosts = append(backupServer.Hosts, hostnames[id])
+		}
+
+		document.Servers = append(document.Servers, backupServer)
+	}
+
+	return document, nil
+}
+
+// readBackup decodes a backup document and checks it can be restored
+func readBackup(data []byte) (*backupDocument, error) {
+	var document backupDocument
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse the backup: %w", err)
+	}
+
+	if document.Version != backupVersion {
+		return nil, fmt.Errorf("unsupported backup version %d, expected %d", document.Version, backupVersion)
+	}
+
+	return &document, nil
+}
+
+// attributes returns the JSON attributes of a model, limited to the keys when they are given.
+// The attribute types encode only their non-zero values, so a missing key is a zero value.
+func attributes(model interface{}, keys []string) map[string]interface{} {
+	data, err := json.Marshal(model)
+	if err != nil {
+		panic(err)
+	}
+
+	var all map[string]interface{}
+	if err := json.Unmarshal(data, &all); err != nil {
+		panic(err)
+	}
+
+	if keys == nil {
+		return all
+	}
+
+	selected := make(map[string]interface{}, len(keys))
+	for _, key := range keys {
+		if value, ok := all[key]; ok {
+			selected[key] = value
+		}
+	}
+
+	return selected
+}
+
+// changedKeys lists the attributes that differ, skipping the ignored ones
+func changedKeys(current, desired map[string]interface{}, ignored ...string) []string {
+	skip := map[string]bool{}
+	for _, key := range ignored {
+		skip[key] = true
+	}
+
+	keys := make(map[string]bool)
+	for key := range current {
+		keys[key] = true
+	}
+	for key := range desired {
+		keys[key] = true
+	}
+
+	changed := make([]string, 0)
+	for key := range keys {
+		if !skip[key] && !reflect.DeepEqual(current[key], desired[key]) {
+			changed = append(changed, key)
+		}
+	}
+	sort.Strings(changed)
+
+	return changed
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBackup(t *testing.T) {
+	server := newFixtureServer(t)
+
+	out := filepath.Join(t.TempDir(), "backup.json")
+	if err := runBackup([]string{"-url", server.URL, "-out", out}, &bytes.Buffer{}); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expected the backup to be readable by the owner only, got %s", info.Mode().Perm())
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, secret := range []string{"OTPSECRET", "otp_secret", `"pin"`, "auth_token", "auth_secret", "hidden", "google-secret-key", "sso"} {
+		if bytes.Contains(data, []byte(secret)) {
+			t.Errorf("the backup contains %s:\n%s", secret, data)
+		}
+	}
+
+	document, err := readBackup(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(document.Organizations) != 2 || len(document.Organizations[0].Users) != 2 || len(document.Organizations[1].Users) != 1 {
+		t.Errorf("expected 2 organizations with 2 and 1 users, got %+v", document.Organizations)
+	}
+	if !document.Organizations[1].AuthApi {
+		t.Error("expected auth_api on the Ops Team organization")
+	}
+
+	if len(document.Servers) != 2 {
+		t.Fatalf("expected 2 servers, got %d", len(document.Servers))
+	}
+
+	prod := document.Servers[0]
+	if prod.Attributes.Name != "prod-vpn" || prod.Attributes.Status != "online" || prod.Attributes.MaxClients != 2000 {
+		t.Errorf("unexpected server attributes %+v", prod.Attributes)
+	}
+	if len(prod.Routes) != 2 {
+		t.Errorf("expected the 2 routes without the virtual one, got %+v", prod.Routes)
+	}
+	if !reflect.DeepEqual(prod.Organizations, []string{"Developers", "Ops Team"}) {
+		t.Errorf("expected the organizations by name, got %q", prod.Organizations)
+	}
+	if !reflect.DeepEqual(prod.Hosts, []string{"pritunl-1.example.com"}) {
+		t.Errorf("expected the hosts by hostname, got %q", prod.Hosts)
+	}
+
+	expectedSettings := map[string]interface{}{
+		"theme":          "dark",
+		"public_address": "vpn.example.com",
+		"server_port":    float64(443),
+		"pin_mode":       "optional",
+	}
+	if !reflect.DeepEqual(document.Settings, expectedSettings) {
+		t.Errorf("expected the settings %v, got %v", expectedSettings, document.Settings)
+	}
+}
+
+func TestReadBackupVersion(t *testing.T) {
+	_, err := readBackup([]byte(`{"version": 2}`))
+	if err == nil || !strings.Contains(err.Error(), "unsupported backup version 2") {
+		t.Errorf("expected an unsupported version error, got %v", err)
+	}
+}
+
+func TestChangedKeys(t *testing.T) {
+	current := map[string]interface{}{"name": "a", "groups": []interface{}{"x"}, "id": "1"}
+	desired := map[string]interface{}{"name": "a", "email": "a@example.com", "id": "2"}
+
+	changed := changedKeys(current, desired, "id")
+	if !reflect.DeepEqual(changed, []string{"email", "groups"}) {
+		t.Errorf("expected email and groups, got %q", changed)
+	}
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	driftFormatText = "text"
+	driftFormatJson = "json"
+)
+
+// unmanagedObject is an object that exists in Pritunl but isn't in the managed IDs
+type unmanagedObject struct {
+	Type   string `json:"type"`
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Parent string `json:"parent,omitempty"`
+}
+
+// stringsFlag collects the values of a repeated flag
+type stringsFlag []string
+
+func (s *stringsFlag) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringsFlag) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+func runDrift(args []string, stdout io.Writer) error {
+	var connection connectionFlags
+	var managedFiles, ids stringsFlag
+	var format string
+
+	flags := flag.NewFlagSet("drift", flag.ContinueOnError)
+	connection.register(flags)
+	flags.Var(&managedFiles, "managed", "File with the managed IDs, one per line, or the output of terraform show -json, - reads stdin. Can be repeated")
+	flags.Var(&ids, "id", "Managed ID, routes are identified by ${server_id}/${route_id}. Can be repeated")
+	flags.StringVar(&format, "format", driftFormatText, "Output format: text or json")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if format != driftFormatText && format != driftFormatJson {
+		return fmt.Errorf("invalid -format %q, expected %s or %s", format, driftFormatText, driftFormatJson)
+	}
+
+	managed := map[string]bool{}
+	for _, id := range ids {
+		managed[id] = true
+	}
+
+	for _, path := range managedFiles {
+		data, err := readInput(path)
+		if err != nil {
+			return err
+		}
+
+		fileIds, err := parseManagedIds(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		for _, id := range fileIds {
+			managed[id] = true
+		}
+	}
+
+	apiClient, err := connection.client()
+	if err != nil {
+		return err
+	}
+
+	inv, err := loadInventory(apiClient)
+	if err != nil {
+		return err
+	}
+
+	unmanaged := findUnmanaged(inv, managed)
+
+	if err := writeUnmanaged(stdout, format, unmanaged); err != nil {
+		return err
+	}
+
+	if len(unmanaged) > 0 {
+		return fmt.Errorf("found %d unmanaged objects", len(unmanaged))
+	}
+
+	return nil
+}
+
+func readInput(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(path)
+}
+
+// parseManagedIds reads the IDs from a list with one ID per line, blank lines and lines
+// starting with # are skipped, or from the JSON output of terraform show -json
+func parseManagedIds(data []byte) ([]string, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		return parseStateIds(trimmed)
+	}
+
+	ids := make([]string, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		ids = append(ids, line)
+	}
+
+	return ids, scanner.Err()
+}
+
+type stateModule struct {
+	Resources []struct {
+		Mode   string                 `json:"mode"`
+		Type   string                 `json:"type"`
+		Values map[string]interface{} `json:"values"`
+	} `json:"resources"`
+	ChildModules []stateModule `json:"child_modules"`
+}
+
+// parseStateIds reads the IDs of the pritunl resources in every module of the state. The data
+// sources are skipped, pritunl_server_output uses the ID of a server it doesn't manage.
+func parseStateIds(data []byte) ([]string, error) {
+	var state struct {
+		Values struct {
+			RootModule stateModule `json:"root_module"`
+		} `json:"values"`
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse the terraform show -json output: %w", err)
+	}
+
+	ids := make([]string, 0)
+
+	var walk func(module stateModule)
+	walk = func(module stateModule) {
+		for _, resource := range module.Resources {
+			if resource.Mode != "managed" || !strings.HasPrefix(resource.Type, "pritunl_") {
+				continue
+			}
+
+			id, _ := resource.Values["id"].(string)
+			if id == "" {
+				continue
+			}
+
+			// route IDs are derived from the network, so they are unique only on a server
+			if resource.Type == "pritunl_route" {
+				serverId, _ := resource.Values["server_id"].(string)
+				id = serverId + "/" + id
+			}
+
+			ids = append(ids, id)
+		}
+
+		for _, child := range module.ChildModules {
+			walk(child)
+		}
+	}
+	walk(state.Values.RootModule)
+
+	return ids, nil
+}
+
+// findUnmanaged lists the organizations, users, servers and routes missing from the managed IDs
+func findUnmanaged(inv *inventory, managed map[string]bool) []unmanagedObject {
+	unmanaged := make([]unmanagedObject, 0)
+
+	for _, organization := range inv.Organizations {
+		if !managed[organization.ID] {
+			unmanaged = append(unmanaged, unmanagedObject{Type: "organization", ID: organization.ID, Name: organization.Name})
+		}
+
+		for _, user := range inv.Users[organization.ID] {
+			if !managed[user.ID] {
+				unmanaged = append(unmanaged, unmanagedObject{Type: "user", ID: user.ID, Name: user.Name, Parent: organization.ID})
+			}
+		}
+	}
+
+	for _, server := range inv.Servers {
+		if !managed[server.ID] {
+			unmanaged = append(unmanaged, unmanagedObject{Type: "server", ID: server.ID, Name: server.Name})
+		}
+
+		for _, route := range inv.Routes[server.ID] {
+			if !managed[server.ID+"/"+route.ID] {
+				unmanaged = append(unmanaged, unmanagedObject{Type: "route", ID: route.ID, Name: route.Network, Parent: server.ID})
+			}
+		}
+	}
+
+	return unmanaged
+}
+
+func writeUnmanaged(w io.Writer, format string, unmanaged []unmanagedObject) error {
+	if format == driftFormatJson {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(unmanaged)
+	}
+
+	if len(unmanaged) == 0 {
+		_, err := fmt.Fprintln(w, "no unmanaged objects")
+		return err
+	}
+
+	table := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "TYPE\tID\tNAME\tPARENT")
+	for _, object := range unmanaged {
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\n", object.Type, object.ID, object.Name, object.Parent)
+	}
+
+	return table.Flush()
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDrift(t *testing.T) {
+	server := newFixtureServer(t)
+
+	var stdout bytes.Buffer
+	err := runDrift([]string{
+		"-url", server.URL,
+		"-format", "json",
+		"-managed", filepath.Join("testdata", "state.json"),
+		"-id", "5f00000000000000000000b2",
+	}, &stdout)
+	if err == nil || err.Error() != "found 4 unmanaged objects" {
+		t.Errorf("expected the unmanaged objects error, got %v", err)
+	}
+
+	var unmanaged []unmanagedObject
+	if err := json.Unmarshal(stdout.Bytes(), &unmanaged); err != nil {
+		t.Fatalf("%s: %s", err, stdout.String())
+	}
+
+	// the virtual routes of the server networks aren't reported, the staging server is only read
+	// by a data source
+	expected := []unmanagedObject{
+		{Type: "organization", ID: "5f00000000000000000000a2", Name: "Ops Team"},
+		{Type: "user", ID: "5f00000000000000000000b4", Name: "alice", Parent: "5f00000000000000000000a2"},
+		{Type: "route", ID: "382e382e382e382f3332", Name: "8.8.8.8/32", Parent: "5f00000000000000000000c1"},
+		{Type: "server", ID: "5f00000000000000000000c2", Name: "staging"},
+	}
+	if !reflect.DeepEqual(unmanaged, expected) {
+		t.Errorf("expected %+v, got %+v", expected, unmanaged)
+	}
+}
+
+func TestDriftText(t *testing.T) {
+	server := newFixtureServer(t)
+
+	var stdout bytes.Buffer
+	err := runDrift([]string{"-url", server.URL, "-id", "5f00000000000000000000a1"}, &stdout)
+	if err == nil {
+		t.Fatal("expected the unmanaged objects error")
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 9 {
+		t.Fatalf("expected a header and 8 objects, got:\n%s", stdout.String())
+	}
+	if fields := strings.Fields(lines[0]); !reflect.DeepEqual(fields, []string{"TYPE", "ID", "NAME", "PARENT"}) {
+		t.Errorf("unexpected header %q", lines[0])
+	}
+	if fields := strings.Fields(lines[1]); !reflect.DeepEqual(fields, []string{"user", "5f00000000000000000000b1", "alice", "5f00000000000000000000a1"}) {
+		t.Errorf("unexpected first object %q", lines[1])
+	}
+}
+
+func TestDriftWithoutUnmanagedObjects(t *testing.T) {
+	server := newFixtureServer(t)
+
+	args := []string{"-url", server.URL}
+	for _, id := range []string{
+		"5f00000000000000000000a1", "5f00000000000000000000a2",
+		"5f00000000000000000000b1", "5f00000000000000000000b2", "5f00000000000000000000b4",
+		"5f00000000000000000000c1", "5f00000000000000000000c2",
+		"5f00000000000000000000c1/31302e302e302e302f3136", "5f00000000000000000000c1/382e382e382e382f3332",
+	} {
+		args = append(args, "-id", id)
+	}
+
+	var stdout bytes.Buffer
+	if err := runDrift(args, &stdout); err != nil {
+		t.Fatal(err)
+	}
+	if stdout.String() != "no unmanaged objects\n" {
+		t.Errorf("unexpected output %q", stdout.String())
+	}
+}
+
+func TestParseManagedIds(t *testing.T) {
+	ids, err := parseManagedIds([]byte("# organizations\n5f1\n\n  5f2  \n5f3/6a1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"5f1", "5f2", "5f3/6a1"}; !reflect.DeepEqual(ids, expected) {
+		t.Errorf("expected %q, got %q", expected, ids)
+	}
+
+	_, err = parseManagedIds([]byte("{not json"))
+	if err == nil {
+		t.Error("expected a parse error")
+	}
+}
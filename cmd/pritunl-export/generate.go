@@ -0,0 +1,411 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/maulid7/terraform-provider-pritunl/pritunl"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	attachmentsInline    = "inline"
+	attachmentsResources = "resources"
+)
+
+func runGenerate(args []string, stdout io.Writer) error {
+	var connection connectionFlags
+	var out, attachments string
+
+	flags := flag.NewFlagSet("generate", flag.ContinueOnError)
+	connection.register(flags)
+	flags.StringVar(&out, "out", ".", "Directory to write the .tf files to")
+	flags.StringVar(&attachments, "attachments", attachmentsInline,
+		"How to generate the server attachments: inline in organization_ids and host_ids, or as attachment resources")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if attachments != attachmentsInline && attachments != attachmentsResources {
+		return fmt.Errorf("invalid -attachments %q, expected %s or %s", attachments, attachmentsInline, attachmentsResources)
+	}
+
+	apiClient, err := connection.client()
+	if err != nil {
+		return err
+	}
+
+	inv, err := loadInventory(apiClient)
+	if err != nil {
+		return err
+	}
+
+	files := generate(inv, attachments == attachmentsResources)
+
+	if err := os.MkdirAll(out, 0755); err != nil {
+		return err
+	}
+
+	for _, name := range sortedKeys(files) {
+		path := filepath.Join(out, name)
+		if err := os.WriteFile(path, files[name], 0644); err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "wrote %s\n", path)
+	}
+
+	fmt.Fprintf(stdout, "exported %s\n", inv)
+
+	return nil
+}
+
+// generate renders the inventory as Terraform configuration with an import block for every
+// resource, the result maps the file names to their content
+func generate(inv *inventory, attachmentResources bool) map[string][]byte {
+	g := &generator{
+		labels:        newLabeler(),
+		organizations: map[string]hcl.Traversal{},
+		servers:       map[string]hcl.Traversal{},
+		hosts:         map[string]hcl.Traversal{},
+	}
+
+	files := map[string]*hclwrite.File{}
+	file := func(name string) *hclwrite.Body {
+		if _, ok := files[name]; !ok {
+			files[name] = hclwrite.NewEmptyFile()
+		}
+		return files[name].Body()
+	}
+
+	// hosts and organizations first, they are referenced by the servers and users
+	for _, host := range inv.Hosts {
+		g.host(file("hosts.tf"), host)
+	}
+
+	for _, organization := range inv.Organizations {
+		g.organization(file("organizations.tf"), organization)
+	}
+
+	for _, organization := range inv.Organizations {
+		for _, user := range inv.Users[organization.ID] {
+			g.user(file("users.tf"), organization, user)
+		}
+	}
+
+	for _, server := range inv.Servers {
+		body := file("servers.tf")
+		organizationIds := inv.ServerOrganizations[server.ID]
+		hostIds := inv.ServerHosts[server.ID]
+
+		g.server(body, server, organizationIds, hostIds, attachmentResources)
+
+		for _, route := range inv.Routes[server.ID] {
+			g.route(body, server, route)
+		}
+
+		if attachmentResources {
+			for _, organizationId := range organizationIds {
+				g.attachment(body, server, "pritunl_server_organization_attachment", "organization_id", organizationId, g.organizations)
+			}
+			for _, hostId := range hostIds {
+				g.attachment(body, server, "pritunl_server_host_attachment", "host_id", hostId, g.hosts)
+			}
+		}
+	}
+
+	result := make(map[string][]byte, len(files))
+	for name, f := range files {
+		result[name] = hclwrite.Format(f.Bytes())
+	}
+
+	return result
+}
+
+// generator renders the resources and remembers the references to them
+type generator struct {
+	labels *labeler
+
+	// references to the id attribute of the generated resources by Pritunl ID
+	organizations map[string]hcl.Traversal
+	servers       map[string]hcl.Traversal
+	hosts         map[string]hcl.Traversal
+}
+
+// resource appends a resource block followed by its import block and returns the resource body
+func (g *generator) resource(body *hclwrite.Body, resourceType, name, importId string) (*hclwrite.Body, hcl.Traversal) {
+	label := g.labels.label(resourceType, name)
+
+	if len(body.Blocks()) > 0 {
+		body.AppendNewline()
+	}
+
+	resource := body.AppendNewBlock("resource", []string{resourceType, label}).Body()
+
+	body.AppendNewline()
+	block := body.AppendNewBlock("import", nil).Body()
+	block.SetAttributeTraversal("to", hcl.Traversal{
+		hcl.TraverseRoot{Name: resourceType},
+		hcl.TraverseAttr{Name: label},
+	})
+	block.SetAttributeValue("id", cty.StringVal(importId))
+
+	return resource, hcl.Traversal{
+		hcl.TraverseRoot{Name: resourceType},
+		hcl.TraverseAttr{Name: label},
+		hcl.TraverseAttr{Name: "id"},
+	}
+}
+
+func (g *generator) organization(body *hclwrite.Body, organization pritunl.Organization) {
+	resource, ref := g.resource(body, "pritunl_organization", organization.Name, organization.ID)
+	g.organizations[organization.ID] = ref
+
+	setString(resource, "name", organization.Name)
+	setBool(resource, "auth_api", organization.AuthApi)
+}
+
+func (g *generator) user(body *hclwrite.Body, organization pritunl.Organization, user pritunl.User) {
+	resource, _ := g.resource(body, "pritunl_user", organization.Name+"_"+user.Name, organization.ID+"/"+user.ID)
+
+	setString(resource, "name", user.Name)
+	setReference(resource, "organization_id", organization.ID, g.organizations)
+	setString(resource, "email", user.Email)
+	setStrings(resource, "groups", user.Groups)
+	setBool(resource, "disabled", user.Disabled)
+	setString(resource, "auth_type", user.AuthType)
+	setStrings(resource, "dns_servers", user.DnsServers)
+	setString(resource, "dns_suffix", user.DnsSuffix)
+	setStrings(resource, "network_links", user.NetworkLinks)
+	setStrings(resource, "mac_addresses", user.MacAddresses)
+	setBool(resource, "client_to_client", user.ClientToClient)
+	setBool(resource, "bypass_secondary", user.BypassSecondary)
+
+	// the null values and the rules left empty are skipped, cty can't build an empty map value
+	rules := make([]cty.Value, 0, len(user.PortForwarding))
+	for _, rule := range user.PortForwarding {
+		values := map[string]cty.Value{}
+		for key, value := range rule {
+			if value != nil {
+				values[key] = cty.StringVal(fmt.Sprint(value))
+			}
+		}
+		if len(values) > 0 {
+			rules = append(rules, cty.MapVal(values))
+		}
+	}
+	if len(rules) > 0 {
+		resource.SetAttributeValue("port_forwarding", cty.ListVal(rules))
+	}
+}
+
+func (g *generator) server(body *hclwrite.Body, server pritunl.Server, organizationIds, hostIds []string, attachmentResources bool) {
+	resource, ref := g.resource(body, "pritunl_server", server.Name, server.ID)
+	g.servers[server.ID] = ref
+
+	setString(resource, "name", server.Name)
+	setString(resource, "protocol", server.Protocol)
+	setInt(resource, "port", server.Port)
+	setString(resource, "cipher", server.Cipher)
+	setString(resource, "hash", server.Hash)
+	setString(resource, "network", server.Network)
+	setString(resource, "network_mode", server.NetworkMode)
+	setString(resource, "network_start", server.NetworkStart)
+	setString(resource, "network_end", server.NetworkEnd)
+	setString(resource, "bind_address", server.BindAddress)
+	setString(resource, "network_wg", server.NetworkWG)
+	setInt(resource, "port_wg", server.PortWG)
+	setStrings(resource, "groups", server.Groups)
+	setStrings(resource, "dns_servers", server.DnsServers)
+	setString(resource, "search_domain", server.SearchDomain)
+	setBool(resource, "sso_auth", server.SsoAuth)
+	setBool(resource, "otp_auth", server.OtpAuth)
+	setBool(resource, "device_auth", server.DeviceAuth)
+	setBool(resource, "dynamic_firewall", server.DynamicFirewall)
+	setBool(resource, "ipv6", server.IPv6)
+	setBool(resource, "ipv6_firewall", server.IPv6Firewall)
+	setInt(resource, "dh_param_bits", server.DhParamBits)
+	setInt(resource, "ping_interval", server.PingInterval)
+	setInt(resource, "ping_timeout", server.PingTimeout)
+	setInt(resource, "link_ping_interval", server.LinkPingInterval)
+	setInt(resource, "link_ping_timeout", server.LinkPingTimeout)
+	setInt(resource, "session_timeout", server.SessionTimeout)
+	setInt(resource, "inactive_timeout", server.InactiveTimeout)
+	setInt(resource, "max_clients", server.MaxClients)
+	setInt(resource, "max_devices", server.MaxDevices)
+	setInt(resource, "mss_fix", server.MssFix)
+	setInt(resource, "replica_count", server.ReplicaCount)
+	setString(resource, "allowed_devices", server.AllowedDevices)
+	setString(resource, "pre_connect_msg", server.PreConnectMsg)
+	setBool(resource, "multi_device", server.MultiDevice)
+	setBool(resource, "debug", server.Debug)
+	setBool(resource, "restrict_routes", server.RestrictRoutes)
+	setBool(resource, "block_outside_dns", server.BlockOutsideDns)
+	setBool(resource, "dns_mapping", server.DnsMapping)
+	setBool(resource, "inter_client", server.InterClient)
+	setBool(resource, "vxlan", server.VxLan)
+	setBool(resource, "lzo_compression", server.LzoCompression)
+	setBool(resource, "jumbo_frames", server.JumboFrames)
+	setBool(resource, "route_dns", server.RouteDns)
+
+	if attachmentResources {
+		resource.SetAttributeValue("manage_attachments", cty.False)
+	} else {
+		setReferences(resource, "organization_ids", organizationIds, g.organizations)
+		setReferences(resource, "host_ids", hostIds, g.hosts)
+	}
+
+	setString(resource, "status", server.Status)
+}
+
+func (g *generator) route(body *hclwrite.Body, server pritunl.Server, route pritunl.Route) {
+	resource, _ := g.resource(body, "pritunl_route", server.Name+"_"+route.Network, server.ID+"/"+route.ID)
+
+	setReference(resource, "server_id", server.ID, g.servers)
+	setString(resource, "network", route.Network)
+	setString(resource, "comment", route.Comment)
+	resource.SetAttributeValue("nat", cty.BoolVal(route.Nat))
+	setBool(resource, "net_gateway", route.NetGateway)
+}
+
+func (g *generator) host(body *hclwrite.Body, host pritunl.Host) {
+	resource, ref := g.resource(body, "pritunl_host", host.Name, host.ID)
+	g.hosts[host.ID] = ref
+
+	setString(resource, "hostname", host.Hostname)
+	setString(resource, "name", host.Name)
+	setString(resource, "public_addr", host.PublicAddr)
+	setString(resource, "public_addr6", host.PublicAddr6)
+	setString(resource, "routed_subnet6", host.RoutedSubnet6)
+	setString(resource, "routed_subnet6_wg", host.RoutedSubnet6WG)
+	setString(resource, "local_addr", host.LocalAddr)
+	setString(resource, "local_addr6", host.LocalAddr6)
+	setString(resource, "link_addr", host.LinkAddr)
+	setString(resource, "sync_address", host.SyncAddress)
+	setString(resource, "availability_group", host.AvailabilityGroup)
+}
+
+// attachment renders a server attachment resource, attribute is organization_id or host_id
+func (g *generator) attachment(body *hclwrite.Body, server pritunl.Server, resourceType, attribute, id string, refs map[string]hcl.Traversal) {
+	name := server.Name + "_" + id
+	if ref, ok := refs[id]; ok {
+		name = server.Name + "_" + ref[1].(hcl.TraverseAttr).Name
+	}
+
+	resource, _ := g.resource(body, resourceType, name, server.ID+"/"+id)
+
+	setReference(resource, "server_id", server.ID, g.servers)
+	setReference(resource, attribute, id, refs)
+}
+
+// Zero values are left out, they match the defaults of the provider
+
+func setString(body *hclwrite.Body, name, value string) {
+	if value != "" {
+		body.SetAttributeValue(name, cty.StringVal(value))
+	}
+}
+
+func setBool(body *hclwrite.Body, name string, value bool) {
+	if value {
+		body.SetAttributeValue(name, cty.True)
+	}
+}
+
+func setInt(body *hclwrite.Body, name string, value int) {
+	if value != 0 {
+		body.SetAttributeValue(name, cty.NumberIntVal(int64(value)))
+	}
+}
+
+func setStrings(body *hclwrite.Body, name string, values []string) {
+	if len(values) == 0 {
+		return
+	}
+
+	list := make([]cty.Value, 0, len(values))
+	for _, value := range values {
+		list = append(list, cty.StringVal(value))
+	}
+	body.SetAttributeValue(name, cty.ListVal(list))
+}
+
+// setReference refers to the generated resource with the ID, or sets the ID when the
+// resource isn't generated
+func setReference(body *hclwrite.Body, name, id string, refs map[string]hcl.Traversal) {
+	if ref, ok := refs[id]; ok {
+		body.SetAttributeTraversal(name, ref)
+		return
+	}
+	setString(body, name, id)
+}
+
+func setReferences(body *hclwrite.Body, name string, ids []string, refs map[string]hcl.Traversal) {
+	if len(ids) == 0 {
+		return
+	}
+
+	tokens := hclwrite.Tokens{{Type: hclsyntax.TokenOBrack, Bytes: []byte("[")}}
+	for i, id := range ids {
+		if i > 0 {
+			tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenComma, Bytes: []byte(",")})
+		}
+
+		if ref, ok := refs[id]; ok {
+			tokens = append(tokens, hclwrite.TokensForTraversal(ref)...)
+		} else {
+			tokens = append(tokens, hclwrite.TokensForValue(cty.StringVal(id))...)
+		}
+	}
+	tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenCBrack, Bytes: []byte("]")})
+
+	body.SetAttributeRaw(name, tokens)
+}
+
+var invalidLabelCharacters = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+// labeler generates unique Terraform resource names from Pritunl names
+type labeler struct {
+	used map[string]bool
+}
+
+func newLabeler() *labeler {
+	return &labeler{used: map[string]bool{}}
+}
+
+// label converts the name to a valid resource name, e.g. "Ops Team" to ops_team, a number
+// is appended when the resource type already has a resource with the name
+func (l *labeler) label(resourceType, name string) string {
+	label := invalidLabelCharacters.ReplaceAllString(strings.ToLower(name), "_")
+	label = strings.Trim(label, "_")
+
+	if label == "" || !(label[0] >= 'a' && label[0] <= 'z') {
+		label = "_" + label
+	}
+
+	unique := label
+	for i := 2; l.used[resourceType+"."+unique]; i++ {
+		unique = fmt.Sprintf("%s_%d", label, i)
+	}
+	l.used[resourceType+"."+unique] = true
+
+	return unique
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
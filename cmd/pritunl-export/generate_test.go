@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/maulid7/terraform-provider-pritunl/pritunl"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// newFixtureServer serves the API responses recorded in testdata/fixture.json by request path
+func newFixtureServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", "fixture.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var responses map[string]json.RawMessage
+	if err := json.Unmarshal(data, &responses); err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response, ok := responses[r.URL.Path]
+		if !ok || r.Method != http.MethodGet {
+			http.Error(w, "not recorded", http.StatusNotFound)
+			return
+		}
+		w.Write(response)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestGenerate(t *testing.T) {
+	server := newFixtureServer(t)
+
+	tests := []struct {
+		name   string
+		args   []string
+		golden string
+	}{
+		{"inline attachments", nil, "inline"},
+		{"attachment resources", []string{"-attachments", "resources"}, "resources"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			out := t.TempDir()
+
+			var stdout bytes.Buffer
+			args := append([]string{"-url", server.URL, "-out", out}, test.args...)
+			if err := runGenerate(args, &stdout); err != nil {
+				t.Fatal(err)
+			}
+
+			if !strings.Contains(stdout.String(), "exported 2 organizations, 3 users, 2 servers, 2 routes, 1 hosts") {
+				t.Errorf("unexpected summary: %s", stdout.String())
+			}
+
+			golden := filepath.Join("testdata", test.golden)
+			compareDirectories(t, out, golden)
+		})
+	}
+}
+
+// compareDirectories compares the generated files with the golden ones, -update overwrites them
+func compareDirectories(t *testing.T, got, golden string) {
+	t.Helper()
+
+	if *update {
+		os.RemoveAll(golden)
+		if err := os.MkdirAll(golden, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err := os.ReadDir(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	goldenFiles, _ := os.ReadDir(golden)
+	if !*update && len(files) != len(goldenFiles) {
+		t.Errorf("expected %d files, got %d", len(goldenFiles), len(files))
+	}
+
+	for _, file := range files {
+		content, err := os.ReadFile(filepath.Join(got, file.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		path := filepath.Join(golden, file.Name())
+		if *update {
+			if err := os.WriteFile(path, content, 0644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+
+		expected, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(content, expected) {
+			t.Errorf("%s doesn't match %s:\n%s", file.Name(), path, content)
+		}
+	}
+}
+
+func TestGenerateInvalidAttachments(t *testing.T) {
+	err := runGenerate([]string{"-url", "http://localhost", "-attachments", "both"}, &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "invalid -attachments") {
+		t.Errorf("expected an invalid -attachments error, got %v", err)
+	}
+}
+
+func TestGeneratePortForwarding(t *testing.T) {
+	organization := pritunl.Organization{ID: "5f00000000000000000000a1", Name: "Developers"}
+	users := []pritunl.User{
+		{ID: "5f00000000000000000000b1", Name: "alice", PortForwarding: []map[string]interface{}{
+			{},
+			{"protocol": "tcp", "port": "80", "dport": nil},
+		}},
+		{ID: "5f00000000000000000000b2", Name: "bob", PortForwarding: []map[string]interface{}{{"port": nil}}},
+	}
+
+	files := generate(&inventory{
+		Organizations: []pritunl.Organization{organization},
+		Users:         map[string][]pritunl.User{organization.ID: users},
+	}, false)
+
+	content := string(files["users.tf"])
+	if strings.Count(content, "port_forwarding") != 1 || !strings.Contains(content, `port_forwarding = [{
+    port     = "80"
+    protocol = "tcp"
+  }]`) {
+		t.Errorf("expected the port forwarding of alice without the empty rule and the null value, got:\n%s", content)
+	}
+	if strings.Contains(content, "<nil>") {
+		t.Errorf("expected no null value, got:\n%s", content)
+	}
+}
+
+func TestLabel(t *testing.T) {
+	labels := newLabeler()
+
+	tests := []struct {
+		resourceType string
+		name         string
+		label        string
+	}{
+		{"pritunl_organization", "Ops Team", "ops_team"},
+		{"pritunl_organization", "ops-team", "ops-team"},
+		{"pritunl_organization", "Ops  Team!", "ops_team_2"},
+		{"pritunl_server", "Ops Team", "ops_team"},
+		{"pritunl_route", "prod_10.0.0.0/16", "prod_10_0_0_0_16"},
+		{"pritunl_server", "1st", "_1st"},
+		{"pritunl_server", "", "_"},
+	}
+
+	for _, test := range tests {
+		if label := labels.label(test.resourceType, test.name); label != test.label {
+			t.Errorf("label(%q, %q) = %q, expected %q", test.resourceType, test.name, label, test.label)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/maulid7/terraform-provider-pritunl/pritunl"
+)
+
+// pritunlUserTypeServer marks the users Pritunl creates for its own servers and links
+const pritunlUserTypeServer = "server"
+
+// inventory holds the objects read from Pritunl, keyed by the ID of their parent
+type inventory struct {
+	Organizations []pritunl.Organization
+	Users         map[string][]pritunl.User
+	Servers       []pritunl.Server
+	Routes        map[string][]pritunl.Route
+	Hosts         []pritunl.Host
+
+	// IDs of the organizations and hosts attached to each server
+	ServerOrganizations map[string][]string
+	ServerHosts         map[string][]string
+}
+
+// loadInventory reads every organization, user, server, route and host and the server attachments
+func loadInventory(apiClient pritunl.Client) (*inventory, error) {
+	inv := &inventory{
+		Users:               map[string][]pritunl.User{},
+		Routes:              map[string][]pritunl.Route{},
+		ServerOrganizations: map[string][]string{},
+		ServerHosts:         map[string][]string{},
+	}
+
+	var err error
+
+	inv.Organizations, err = apiClient.GetOrganizations()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, organization := range inv.Organizations {
+		users, err := apiClient.GetUsers(organization.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, user := range users {
+			if user.Type == pritunlUserTypeServer {
+				continue
+			}
+			inv.Users[organization.ID] = append(inv.Users[organization.ID], user)
+		}
+	}
+
+	inv.Servers, err = apiClient.GetServers()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, server := range inv.Servers {
+		routes, err := apiClient.GetRoutesByServer(server.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, route := range routes {
+			// virtual routes are created by Pritunl for the server network
+			if route.VirtualNetwork {
+				continue
+			}
+			inv.Routes[server.ID] = append(inv.Routes[server.ID], route)
+		}
+
+		organizations, err := apiClient.GetOrganizationsByServer(server.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, organization := range organizations {
+			inv.ServerOrganizations[server.ID] = append(inv.ServerOrganizations[server.ID], organization.ID)
+		}
+
+		hosts, err := apiClient.GetHostsByServer(server.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, host := range hosts {
+			inv.ServerHosts[server.ID] = append(inv.ServerHosts[server.ID], host.ID)
+		}
+	}
+
+	inv.Hosts, err = apiClient.GetHosts()
+	if err != nil {
+		return nil, err
+	}
+
+	return inv, nil
+}
+
+func (inv *inventory) String() string {
+	users, routes := 0, 0
+	for _, list := range inv.Users {
+		users += len(list)
+	}
+	for _, list := range inv.Routes {
+		routes += len(list)
+	}
+
+	return fmt.Sprintf("%d organizations, %d users, %d servers, %d routes, %d hosts",
+		len(inv.Organizations), users, len(inv.Servers), routes, len(inv.Hosts))
+}
@@ -0,0 +1,97 @@
+// Command pritunl-export reads the configuration of a live Pritunl server, generates the
+// Terraform configuration for it, reports the objects Terraform doesn't manage and backs
+// up and restores the configuration.
+//
+// Usage:
+//
+//	pritunl-export generate [-out dir] [-attachments inline|resources]
+//	pritunl-export drift [-managed file] [-id id] [-format text|json]
+//	pritunl-export backup [-out file]
+//	pritunl-export restore [-in file] [-dry-run]
+//
+// The connection uses the same environment variables as the provider: PRITUNL_URL,
+// PRITUNL_TOKEN, PRITUNL_SECRET and PRITUNL_INSECURE.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/maulid7/terraform-provider-pritunl/pritunl"
+)
+
+// command runs a subcommand with its arguments, the output is written to stdout
+type command func(args []string, stdout io.Writer) error
+
+var commands = map[string]command{
+	"generate": runGenerate,
+	"drift":    runDrift,
+	"backup":   runBackup,
+	"restore":  runRestore,
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage(os.Stderr)
+		os.Exit(2)
+	}
+
+	run, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n", os.Args[1])
+		usage(os.Stderr)
+		os.Exit(2)
+	}
+
+	if err := run(os.Args[2:], os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "pritunl-export %s: %s\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
+
+func usage(w io.Writer) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(w, "usage: pritunl-export <command> [flags]")
+	fmt.Fprintln(w, "commands:")
+	for _, name := range names {
+		fmt.Fprintf(w, "  %s\n", name)
+	}
+}
+
+// connectionFlags holds the Pritunl API connection settings shared by the commands
+type connectionFlags struct {
+	url      string
+	token    string
+	secret   string
+	insecure bool
+}
+
+func (c *connectionFlags) register(flags *flag.FlagSet) {
+	insecure, _ := strconv.ParseBool(os.Getenv("PRITUNL_INSECURE"))
+
+	flags.StringVar(&c.url, "url", os.Getenv("PRITUNL_URL"), "Pritunl API URL, defaults to PRITUNL_URL")
+	flags.StringVar(&c.token, "token", os.Getenv("PRITUNL_TOKEN"), "Pritunl API token, defaults to PRITUNL_TOKEN")
+	flags.StringVar(&c.secret, "secret", os.Getenv("PRITUNL_SECRET"), "Pritunl API secret, defaults to PRITUNL_SECRET")
+	flags.BoolVar(&c.insecure, "insecure", insecure, "Skip the TLS verification, defaults to PRITUNL_INSECURE")
+}
+
+func (c *connectionFlags) client() (pritunl.Client, error) {
+	if c.url == "" {
+		return nil, fmt.Errorf("the Pritunl URL is not set, use -url or PRITUNL_URL")
+	}
+
+	return pritunl.NewClient(c.url,
+		pritunl.WithCredentials(c.token, c.secret),
+		pritunl.WithInsecure(c.insecure),
+		pritunl.WithUserAgent("pritunl-export"),
+	), nil
+}
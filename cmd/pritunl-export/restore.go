@@ -0,0 +1,555 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/maulid7/terraform-provider-pritunl/pritunl"
+)
+
+func runRestore(args []string, stdout io.Writer) error {
+	var connection connectionFlags
+	var in string
+	var dryRun bool
+
+	flags := flag.NewFlagSet("restore", flag.ContinueOnError)
+	connection.register(flags)
+	flags.StringVar(&in, "in", "-", "Backup file to restore, - reads stdin")
+	flags.BoolVar(&dryRun, "dry-run", false, "Print the changes without applying them")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	data, err := readInput(in)
+	if err != nil {
+		return err
+	}
+
+	document, err := readBackup(data)
+	if err != nil {
+		return err
+	}
+
+	apiClient, err := connection.client()
+	if err != nil {
+		return err
+	}
+
+	r := &restorer{apiClient: apiClient, dryRun: dryRun, out: stdout}
+	if err := r.restore(document); err != nil {
+		return err
+	}
+
+	switch {
+	case r.changes == 0:
+		fmt.Fprintln(stdout, "no changes")
+	case dryRun:
+		fmt.Fprintf(stdout, "%d changes, nothing was applied in the dry run\n", r.changes)
+	default:
+		fmt.Fprintf(stdout, "%d changes applied\n", r.changes)
+	}
+
+	return nil
+}
+
+// restorer replays a backup document, the objects that already match the backup are left
+// untouched and the objects missing from the backup are never deleted, so a restore can be
+// repeated and applied to a partially populated Pritunl.
+//
+// Each change is printed as a line starting with + for a creation, ~ for an update or - for
+// a deletion. In the dry run the changes are only printed.
+type restorer struct {
+	apiClient pritunl.Client
+	dryRun    bool
+	out       io.Writer
+	changes   int
+}
+
+func (r *restorer) change(format string, args ...interface{}) {
+	r.changes++
+	fmt.Fprintf(r.out, format+"\n", args...)
+}
+
+func (r *restorer) warn(format string, args ...interface{}) {
+	fmt.Fprintf(r.out, "! "+format+"\n", args...)
+}
+
+func (r *restorer) restore(document *backupDocument) error {
+	if err := r.restoreSettings(document.Settings); err != nil {
+		return err
+	}
+
+	hostIds, err := r.restoreHosts(document.Hosts)
+	if err != nil {
+		return err
+	}
+
+	organizationIds, err := r.restoreOrganizations(document.Organizations)
+	if err != nil {
+		return err
+	}
+
+	for _, server := range document.Servers {
+		if err := r.restoreServer(server, organizationIds, hostIds); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *restorer) restoreSettings(desired map[string]interface{}) error {
+	if desired == nil {
+		return nil
+	}
+
+	current, err := r.apiClient.GetSettings()
+	if err != nil {
+		return err
+	}
+
+	changed := changedKeys(attributes(current, settingsKeys), desired)
+	if len(changed) == 0 {
+		return nil
+	}
+
+	r.change("~ settings: %s", strings.Join(changed, ", "))
+	if r.dryRun {
+		return nil
+	}
+
+	data, err := json.Marshal(desired)
+	if err != nil {
+		return err
+	}
+
+	var settings pritunl.Settings
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return err
+	}
+	settings.ForceSendFields = changed
+
+	return r.apiClient.UpdateSettings(&settings)
+}
+
+// restoreHosts updates the hosts and returns their IDs by hostname. Hosts register
+// themselves in Pritunl, so the missing ones are skipped.
+func (r *restorer) restoreHosts(hosts []pritunl.Host) (map[string]string, error) {
+	current, err := r.apiClient.GetHosts()
+	if err != nil {
+		return nil, err
+	}
+
+	currentHosts := map[string]pritunl.Host{}
+	for _, host := range current {
+		currentHosts[host.Hostname] = host
+	}
+
+	hostIds := map[string]string{}
+	for _, host := range hosts {
+		currentHost, ok := currentHosts[host.Hostname]
+		if !ok {
+			r.warn("host %q doesn't exist, start Pritunl on it and restore again", host.Hostname)
+			continue
+		}
+		hostIds[host.Hostname] = currentHost.ID
+
+		changed := changedKeys(attributes(currentHost, hostKeys), attributes(host, hostKeys))
+		if len(changed) == 0 {
+			continue
+		}
+
+		r.change("~ host %q: %s", host.Hostname, strings.Join(changed, ", "))
+		if r.dryRun {
+			continue
+		}
+
+		host.ID = currentHost.ID
+		host.Status = currentHost.Status
+		if err := r.apiClient.UpdateHost(currentHost.ID, &host); err != nil {
+			return nil, err
+		}
+	}
+
+	return hostIds, nil
+}
+
+// restoreOrganizations restores the organizations with their users and returns their IDs
+// by name, the ID is empty for the organizations created in the dry run
+func (r *restorer) restoreOrganizations(organizations []backupOrganization) (map[string]string, error) {
+	current, err := r.apiClient.GetOrganizations()
+	if err != nil {
+		return nil, err
+	}
+
+	currentOrganizations := map[string]pritunl.Organization{}
+	for _, organization := range current {
+		currentOrganizations[organization.Name] = organization
+	}
+
+	organizationIds := map[string]string{}
+	for _, organization := range organizations {
+		currentOrganization, ok := currentOrganizations[organization.Name]
+		if !ok {
+			r.change("+ organization %q", organization.Name)
+			if !r.dryRun {
+				created, err := r.apiClient.CreateOrganization(organization.Name)
+				if err != nil {
+					return nil, err
+				}
+				currentOrganization = *created
+			}
+		} else if currentOrganization.AuthApi != organization.AuthApi {
+			r.change("~ organization %q: auth_api", organization.Name)
+		}
+
+		organizationId := currentOrganization.ID
+		organizationIds[organization.Name] = organizationId
+
+		if !r.dryRun && currentOrganization.AuthApi != organization.AuthApi {
+			err := r.apiClient.UpdateOrganization(organizationId, &pritunl.Organization{
+				ID:      organizationId,
+				Name:    organization.Name,
+				AuthApi: organization.AuthApi,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if err := r.restoreUsers(organization, organizationId); err != nil {
+			return nil, err
+		}
+	}
+
+	return organizationIds, nil
+}
+
+func (r *restorer) restoreUsers(organization backupOrganization, organizationId string) error {
+	currentUsers := map[string]pritunl.User{}
+
+	// an organization created in the dry run has no users yet
+	if organizationId != "" {
+		users, err := r.apiClient.GetUsers(organizationId)
+		if err != nil {
+			return err
+		}
+		for _, user := range users {
+			if user.Type != pritunlUserTypeServer {
+				currentUsers[user.Name] = user
+			}
+		}
+	}
+
+	for _, user := range organization.Users {
+		desired := pritunl.User(user)
+		desired.Organization = organizationId
+
+		currentUser, ok := currentUsers[user.Name]
+		if !ok {
+			r.change("+ user \"%s/%s\"", organization.Name, user.Name)
+			if r.dryRun {
+				continue
+			}
+
+			desired.ID = ""
+			if _, err := r.apiClient.CreateUser(desired); err != nil {
+				return err
+			}
+			continue
+		}
+
+		changed := changedKeys(attributes(userAttributes(currentUser), userKeys), attributes(user, userKeys))
+		if len(changed) == 0 {
+			continue
+		}
+
+		r.change("~ user \"%s/%s\": %s", organization.Name, user.Name, strings.Join(changed, ", "))
+		if r.dryRun {
+			continue
+		}
+
+		desired.ID = currentUser.ID
+		desired.ForceSendFields = changed
+		if err := r.apiClient.UpdateUser(currentUser.ID, &desired); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *restorer) restoreServer(server backupServer, organizationIds, hostIds map[string]string) (err error) {
+	desired := pritunl.Server(server.Attributes)
+	name := desired.Name
+
+	current, err := r.findServer(name)
+	if err != nil {
+		return err
+	}
+
+	created := current == nil
+	if created {
+		r.change("+ server %q", name)
+
+		// a server created in the dry run has no ID, routes or attachments
+		current = &pritunl.Server{Name: name, Status: pritunl.ServerStatusOffline}
+		if !r.dryRun {
+			current, err = r.apiClient.CreateServer(pritunl.ServerCreateRequest{
+				Name:      name,
+				Protocol:  desired.Protocol,
+				Port:      desired.Port,
+				Network:   desired.Network,
+				PortWG:    desired.PortWG,
+				NetworkWG: desired.NetworkWG,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	serverId := current.ID
+	status := pritunl.ServerStatusOffline
+	if current.Status == pritunl.ServerStatusOnline {
+		status = pritunl.ServerStatusOnline
+	}
+	online := status == pritunl.ServerStatusOnline
+
+	// the server is stopped before its first change, Pritunl rejects changes to online servers
+	stop := func() error {
+		if !online || r.dryRun {
+			return nil
+		}
+		online = false
+		return r.apiClient.StopServer(serverId)
+	}
+
+	// a failed change doesn't leave a server that was online stopped
+	defer func() {
+		if err != nil && status == pritunl.ServerStatusOnline && !online {
+			if startErr := r.apiClient.StartServer(serverId); startErr != nil {
+				err = fmt.Errorf("%w, starting the server %q again failed: %v", err, name, startErr)
+			}
+		}
+	}()
+
+	changed := changedKeys(attributes(serverAttributes(*current), nil), attributes(server.Attributes, nil), serverIgnoredKeys...)
+	if len(changed) > 0 {
+		// the attributes of a new server are part of its creation
+		if !created {
+			r.change("~ server %q: %s", name, strings.Join(changed, ", "))
+		}
+
+		if !r.dryRun {
+			if err := stop(); err != nil {
+				return err
+			}
+
+			update := desired
+			update.ID = serverId
+			update.Status = ""
+			update.ForceSendFields = changed
+			if err := r.apiClient.UpdateServer(serverId, &update); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := r.restoreRoutes(server, serverId, created, stop); err != nil {
+		return err
+	}
+
+	if err := r.restoreAttachments(server, serverId, organizationIds, hostIds, stop); err != nil {
+		return err
+	}
+
+	if desired.Status == "" || desired.Status == status {
+		// restart the servers stopped for the changes
+		if status == pritunl.ServerStatusOnline && !online {
+			// the deferred restart isn't repeated when this one fails
+			online = true
+			return r.apiClient.StartServer(serverId)
+		}
+		return nil
+	}
+
+	r.change("~ server %q: status %s -> %s", name, status, desired.Status)
+	if r.dryRun {
+		return nil
+	}
+
+	if desired.Status == pritunl.ServerStatusOnline {
+		return r.apiClient.StartServer(serverId)
+	}
+	return stop()
+}
+
+func (r *restorer) findServer(name string) (*pritunl.Server, error) {
+	servers, err := r.apiClient.GetServers()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, server := range servers {
+		if server.Name == name {
+			return &server, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// restoreRoutes adds and updates the routes, the routes Pritunl adds to a new server such as
+// 0.0.0.0/0 are deleted when they aren't in the backup
+func (r *restorer) restoreRoutes(server backupServer, serverId string, created bool, stop func() error) error {
+	name := server.Attributes.Name
+
+	currentRoutes := map[string]pritunl.Route{}
+	if serverId != "" {
+		routes, err := r.apiClient.GetRoutesByServer(serverId)
+		if err != nil {
+			return err
+		}
+
+		for _, route := range routes {
+			if !route.VirtualNetwork {
+				currentRoutes[route.Network] = route
+			}
+		}
+	}
+
+	desiredNetworks := map[string]bool{}
+	for _, route := range server.Routes {
+		desiredNetworks[route.Network] = true
+
+		currentRoute, ok := currentRoutes[route.Network]
+		if ok && currentRoute.Comment == route.Comment && currentRoute.Nat == route.Nat && currentRoute.NetGateway == route.NetGateway {
+			continue
+		}
+
+		if ok {
+			r.change("~ route %q %s", name, route.Network)
+		} else {
+			r.change("+ route %q %s", name, route.Network)
+		}
+		if r.dryRun {
+			continue
+		}
+
+		if err := stop(); err != nil {
+			return err
+		}
+
+		route := pritunl.Route{Network: route.Network, Comment: route.Comment, Nat: route.Nat, NetGateway: route.NetGateway}
+		var err error
+		if ok {
+			err = r.apiClient.UpdateRouteOnServer(serverId, route)
+		} else {
+			_, err = r.apiClient.AddRouteToServer(serverId, route)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if !created {
+		return nil
+	}
+
+	for _, network := range sortedKeys(currentRoutes) {
+		if desiredNetworks[network] {
+			continue
+		}
+		route := currentRoutes[network]
+
+		r.change("- route %q %s", name, network)
+		if r.dryRun {
+			continue
+		}
+
+		if err := stop(); err != nil {
+			return err
+		}
+		if err := r.apiClient.DeleteRouteFromServer(serverId, route); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *restorer) restoreAttachments(server backupServer, serverId string, organizationIds, hostIds map[string]string, stop func() error) error {
+	name := server.Attributes.Name
+
+	// IDs of the attached organizations and hosts
+	attached := map[string]bool{}
+	if serverId != "" {
+		organizations, err := r.apiClient.GetOrganizationsByServer(serverId)
+		if err != nil {
+			return err
+		}
+		for _, organization := range organizations {
+			attached[organization.ID] = true
+		}
+
+		hosts, err := r.apiClient.GetHostsByServer(serverId)
+		if err != nil {
+			return err
+		}
+		for _, host := range hosts {
+			attached[host.ID] = true
+		}
+	}
+
+	for _, organization := range server.Organizations {
+		organizationId, ok := organizationIds[organization]
+		if !ok {
+			r.warn("organization %q attached to the server %q isn't in the backup", organization, name)
+			continue
+		}
+		if organizationId != "" && attached[organizationId] {
+			continue
+		}
+
+		r.change("+ attachment %q organization %q", name, organization)
+		if r.dryRun {
+			continue
+		}
+
+		if err := stop(); err != nil {
+			return err
+		}
+		if err := r.apiClient.AttachOrganizationToServer(organizationId, serverId); err != nil {
+			return err
+		}
+	}
+
+	for _, hostname := range server.Hosts {
+		hostId, ok := hostIds[hostname]
+		if !ok {
+			r.warn("host %q attached to the server %q doesn't exist", hostname, name)
+			continue
+		}
+		if attached[hostId] {
+			continue
+		}
+
+		r.change("+ attachment %q host %q", name, hostname)
+		if r.dryRun {
+			continue
+		}
+
+		if err := stop(); err != nil {
+			return err
+		}
+		if err := r.apiClient.AttachHostToServer(hostId, serverId); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,466 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/maulid7/terraform-provider-pritunl/pritunl"
+)
+
+// memoryClient is an in-memory Pritunl holding the objects the restore command manages
+type memoryClient struct {
+	pritunl.Client
+
+	lastId              int
+	settings            pritunl.Settings
+	hosts               []pritunl.Host
+	organizations       []pritunl.Organization
+	users               map[string][]pritunl.User
+	servers             []pritunl.Server
+	routes              map[string][]pritunl.Route
+	serverOrganizations map[string][]string
+	serverHosts         map[string][]string
+
+	// number of requests changing an object
+	writes int
+}
+
+func newMemoryClient(hosts ...pritunl.Host) *memoryClient {
+	return &memoryClient{
+		hosts:               hosts,
+		users:               map[string][]pritunl.User{},
+		routes:              map[string][]pritunl.Route{},
+		serverOrganizations: map[string][]string{},
+		serverHosts:         map[string][]string{},
+	}
+}
+
+func (c *memoryClient) newId() string {
+	c.lastId++
+	return fmt.Sprintf("%024d", c.lastId)
+}
+
+func (c *memoryClient) GetSettings() (*pritunl.Settings, error) {
+	settings := c.settings
+	return &settings, nil
+}
+
+func (c *memoryClient) UpdateSettings(settings *pritunl.Settings) error {
+	c.writes++
+
+	// the API updates the attributes present in the payload
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, &c.settings)
+}
+
+func (c *memoryClient) GetHosts() ([]pritunl.Host, error) {
+	return append([]pritunl.Host{}, c.hosts...), nil
+}
+
+func (c *memoryClient) UpdateHost(id string, host *pritunl.Host) error {
+	c.writes++
+	for i := range c.hosts {
+		if c.hosts[i].ID == id {
+			c.hosts[i] = *host
+			return nil
+		}
+	}
+	return fmt.Errorf("host %s not found", id)
+}
+
+func (c *memoryClient) GetOrganizations() ([]pritunl.Organization, error) {
+	return append([]pritunl.Organization{}, c.organizations...), nil
+}
+
+func (c *memoryClient) CreateOrganization(name string) (*pritunl.Organization, error) {
+	c.writes++
+	organization := pritunl.Organization{ID: c.newId(), Name: name}
+	c.organizations = append(c.organizations, organization)
+	return &organization, nil
+}
+
+func (c *memoryClient) UpdateOrganization(id string, organization *pritunl.Organization) error {
+	c.writes++
+	for i := range c.organizations {
+		if c.organizations[i].ID == id {
+			c.organizations[i].Name = organization.Name
+			c.organizations[i].AuthApi = organization.AuthApi
+			return nil
+		}
+	}
+	return fmt.Errorf("organization %s not found", id)
+}
+
+func (c *memoryClient) GetUsers(orgId string) ([]pritunl.User, error) {
+	return append([]pritunl.User{}, c.users[orgId]...), nil
+}
+
+func (c *memoryClient) CreateUser(user pritunl.User) (*pritunl.User, error) {
+	c.writes++
+	user.ID = c.newId()
+	c.users[user.Organization] = append(c.users[user.Organization], user)
+	return &user, nil
+}
+
+func (c *memoryClient) UpdateUser(id string, user *pritunl.User) error {
+	c.writes++
+	users := c.users[user.Organization]
+	for i := range users {
+		if users[i].ID == id {
+			users[i] = *user
+			users[i].ForceSendFields = nil
+			return nil
+		}
+	}
+	return fmt.Errorf("user %s not found", id)
+}
+
+func (c *memoryClient) GetServers() ([]pritunl.Server, error) {
+	return append([]pritunl.Server{}, c.servers...), nil
+}
+
+func (c *memoryClient) server(id string) *pritunl.Server {
+	for i := range c.servers {
+		if c.servers[i].ID == id {
+			return &c.servers[i]
+		}
+	}
+	return nil
+}
+
+// CreateServer adds the virtual route of the server network and the default route like Pritunl
+func (c *memoryClient) CreateServer(request pritunl.ServerCreateRequest) (*pritunl.Server, error) {
+	c.writes++
+	server := request.Server()
+	server.ID = c.newId()
+	server.Status = pritunl.ServerStatusOffline
+	server.ForceSendFields = nil
+	c.servers = append(c.servers, *server)
+
+	c.routes[server.ID] = []pritunl.Route{
+		{Network: server.Network, Nat: true, VirtualNetwork: true},
+		{Network: "0.0.0.0/0", Nat: true},
+	}
+
+	return server, nil
+}
+
+func (c *memoryClient) UpdateServer(id string, server *pritunl.Server) error {
+	c.writes++
+	current := c.server(id)
+	if current == nil {
+		return fmt.Errorf("server %s not found", id)
+	}
+	if current.Status == pritunl.ServerStatusOnline {
+		return fmt.Errorf("server %s must be offline to be updated", id)
+	}
+
+	*current = *server
+	current.Status = pritunl.ServerStatusOffline
+	current.ForceSendFields = nil
+	return nil
+}
+
+func (c *memoryClient) setServerStatus(id, status string) error {
+	c.writes++
+	server := c.server(id)
+	if server == nil {
+		return fmt.Errorf("server %s not found", id)
+	}
+	server.Status = status
+	return nil
+}
+
+func (c *memoryClient) StartServer(id string) error {
+	return c.setServerStatus(id, pritunl.ServerStatusOnline)
+}
+
+func (c *memoryClient) StopServer(id string) error {
+	return c.setServerStatus(id, pritunl.ServerStatusOffline)
+}
+
+// requireOffline fails like Pritunl when an online server is changed
+func (c *memoryClient) requireOffline(id string) error {
+	c.writes++
+	server := c.server(id)
+	if server == nil {
+		return fmt.Errorf("server %s not found", id)
+	}
+	if server.Status == pritunl.ServerStatusOnline {
+		return fmt.Errorf("server %s must be offline to be changed", id)
+	}
+	return nil
+}
+
+func (c *memoryClient) GetRoutesByServer(serverId string) ([]pritunl.Route, error) {
+	routes := make([]pritunl.Route, 0)
+	for _, route := range c.routes[serverId] {
+		route.ID = route.GetID()
+		routes = append(routes, route)
+	}
+	return routes, nil
+}
+
+func (c *memoryClient) AddRouteToServer(serverId string, route pritunl.Route) (*pritunl.Route, error) {
+	if err := c.requireOffline(serverId); err != nil {
+		return nil, err
+	}
+	c.routes[serverId] = append(c.routes[serverId], route)
+	return &route, nil
+}
+
+func (c *memoryClient) UpdateRouteOnServer(serverId string, route pritunl.Route) error {
+	if err := c.requireOffline(serverId); err != nil {
+		return err
+	}
+	routes := c.routes[serverId]
+	for i := range routes {
+		if routes[i].Network == route.Network {
+			routes[i] = route
+			return nil
+		}
+	}
+	return fmt.Errorf("route %s not found", route.Network)
+}
+
+func (c *memoryClient) DeleteRouteFromServer(serverId string, route pritunl.Route) error {
+	if err := c.requireOffline(serverId); err != nil {
+		return err
+	}
+	routes := make([]pritunl.Route, 0)
+	for _, current := range c.routes[serverId] {
+		if current.Network != route.Network {
+			routes = append(routes, current)
+		}
+	}
+	c.routes[serverId] = routes
+	return nil
+}
+
+func (c *memoryClient) GetOrganizationsByServer(serverId string) ([]pritunl.Organization, error) {
+	organizations := make([]pritunl.Organization, 0)
+	for _, id := range c.serverOrganizations[serverId] {
+		organizations = append(organizations, pritunl.Organization{ID: id})
+	}
+	return organizations, nil
+}
+
+func (c *memoryClient) AttachOrganizationToServer(organizationId, serverId string) error {
+	if err := c.requireOffline(serverId); err != nil {
+		return err
+	}
+	c.serverOrganizations[serverId] = append(c.serverOrganizations[serverId], organizationId)
+	return nil
+}
+
+func (c *memoryClient) GetHostsByServer(serverId string) ([]pritunl.Host, error) {
+	hosts := make([]pritunl.Host, 0)
+	for _, id := range c.serverHosts[serverId] {
+		hosts = append(hosts, pritunl.Host{ID: id})
+	}
+	return hosts, nil
+}
+
+func (c *memoryClient) AttachHostToServer(hostId, serverId string) error {
+	if err := c.requireOffline(serverId); err != nil {
+		return err
+	}
+	c.serverHosts[serverId] = append(c.serverHosts[serverId], hostId)
+	return nil
+}
+
+// fixtureBackup backs up the recorded fixture
+func fixtureBackup(t *testing.T) *backupDocument {
+	t.Helper()
+
+	server := newFixtureServer(t)
+	document, err := backup(pritunl.NewClient(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return document
+}
+
+func restoreDocument(t *testing.T, apiClient pritunl.Client, document *backupDocument, dryRun bool) (*restorer, string) {
+	t.Helper()
+
+	var out bytes.Buffer
+	r := &restorer{apiClient: apiClient, dryRun: dryRun, out: &out}
+	if err := r.restore(document); err != nil {
+		t.Fatalf("%s\n%s", err, out.String())
+	}
+	return r, out.String()
+}
+
+func TestRestore(t *testing.T) {
+	document := fixtureBackup(t)
+
+	// the host registered itself with another address
+	apiClient := newMemoryClient(pritunl.Host{ID: "host", Name: "pritunl-1", Hostname: "pritunl-1.example.com", PublicAddr: "198.51.100.1", Status: "online"})
+
+	r, out := restoreDocument(t, apiClient, document, false)
+
+	for _, line := range []string{
+		`~ settings: pin_mode, public_address, server_port, theme`,
+		`~ host "pritunl-1.example.com": availability_group, local_addr, public_addr`,
+		`+ organization "Developers"`,
+		`+ user "Developers/alice"`,
+		`+ user "Ops Team/alice"`,
+		`+ server "prod-vpn"`,
+		`+ route "prod-vpn" 10.0.0.0/16`,
+		`- route "prod-vpn" 0.0.0.0/0`,
+		`+ attachment "prod-vpn" organization "Ops Team"`,
+		`+ attachment "prod-vpn" host "pritunl-1.example.com"`,
+		`~ server "prod-vpn": status offline -> online`,
+		`+ server "staging"`,
+	} {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("expected the change %q in:\n%s", line, out)
+		}
+	}
+	if r.changes != 17 {
+		t.Errorf("expected 17 changes, got %d:\n%s", r.changes, out)
+	}
+
+	restored, err := backup(apiClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(restored.Settings, document.Settings) {
+		t.Errorf("expected the settings %v, got %v", document.Settings, restored.Settings)
+	}
+	if restored.Hosts[0].PublicAddr != "203.0.113.10" {
+		t.Errorf("expected the host address to be restored, got %s", restored.Hosts[0].PublicAddr)
+	}
+
+	for i, organization := range document.Organizations {
+		restoredOrganization := restored.Organizations[i]
+		if restoredOrganization.Name != organization.Name || restoredOrganization.AuthApi != organization.AuthApi {
+			t.Errorf("expected the organization %+v, got %+v", organization, restoredOrganization)
+		}
+		for j, user := range organization.Users {
+			if changed := changedKeys(attributes(restoredOrganization.Users[j], userKeys), attributes(user, userKeys)); len(changed) > 0 {
+				t.Errorf("the user %s/%s differs: %q", organization.Name, user.Name, changed)
+			}
+		}
+	}
+
+	for i, server := range document.Servers {
+		restoredServer := restored.Servers[i]
+		if changed := changedKeys(attributes(restoredServer.Attributes, nil), attributes(server.Attributes, nil), "id"); len(changed) > 0 {
+			t.Errorf("the server %s differs: %q", server.Attributes.Name, changed)
+		}
+		if !reflect.DeepEqual(restoredServer.Organizations, server.Organizations) || !reflect.DeepEqual(restoredServer.Hosts, server.Hosts) {
+			t.Errorf("expected the attachments %q %q, got %q %q", server.Organizations, server.Hosts, restoredServer.Organizations, restoredServer.Hosts)
+		}
+		if len(restoredServer.Routes) != len(server.Routes) {
+			t.Errorf("expected the routes %+v, got %+v", server.Routes, restoredServer.Routes)
+		}
+	}
+
+	// restoring again finds nothing to change
+	writes := apiClient.writes
+	r, out = restoreDocument(t, apiClient, document, false)
+	if r.changes != 0 || apiClient.writes != writes {
+		t.Errorf("expected the second restore to change nothing, got %d changes:\n%s", r.changes, out)
+	}
+}
+
+func TestRestorePartial(t *testing.T) {
+	document := fixtureBackup(t)
+
+	apiClient := newMemoryClient(pritunl.Host{ID: "host", Name: "pritunl-1", Hostname: "pritunl-1.example.com"})
+	restoreDocument(t, apiClient, document, false)
+
+	// change a user and a route and remove an attachment behind the backup's back
+	apiClient.users[apiClient.organizations[0].ID][0].Email = "changed@example.com"
+	prodId := apiClient.servers[0].ID
+	apiClient.StopServer(prodId)
+	apiClient.UpdateRouteOnServer(prodId, pritunl.Route{Network: "10.0.0.0/16", Comment: "changed", Nat: true})
+	apiClient.serverHosts[prodId] = nil
+
+	r, out := restoreDocument(t, apiClient, document, false)
+
+	expected := strings.Join([]string{
+		`~ user "Developers/alice": email`,
+		`~ route "prod-vpn" 10.0.0.0/16`,
+		`+ attachment "prod-vpn" host "pritunl-1.example.com"`,
+		`~ server "prod-vpn": status offline -> online`,
+	}, "\n") + "\n"
+	if out != expected || r.changes != 4 {
+		t.Errorf("expected the changes:\n%s\ngot:\n%s", expected, out)
+	}
+
+	if apiClient.users[apiClient.organizations[0].ID][0].Email != "alice@example.com" {
+		t.Error("expected the user email to be restored")
+	}
+	if apiClient.servers[0].Status != pritunl.ServerStatusOnline {
+		t.Error("expected the server to be started")
+	}
+}
+
+// attachFailingClient fails to attach the hosts
+type attachFailingClient struct {
+	*memoryClient
+}
+
+func (c attachFailingClient) AttachHostToServer(hostId, serverId string) error {
+	return errors.New("attaching failed")
+}
+
+func TestRestoreRestartsOnError(t *testing.T) {
+	document := fixtureBackup(t)
+
+	apiClient := newMemoryClient(pritunl.Host{ID: "host", Name: "pritunl-1", Hostname: "pritunl-1.example.com"})
+	restoreDocument(t, apiClient, document, false)
+
+	// the online server is stopped for the missing attachment
+	prodId := apiClient.servers[0].ID
+	apiClient.serverHosts[prodId] = nil
+
+	var out bytes.Buffer
+	r := &restorer{apiClient: attachFailingClient{apiClient}, out: &out}
+	if err := r.restore(document); err == nil || !strings.Contains(err.Error(), "attaching failed") {
+		t.Fatalf("expected the attachment error, got %v", err)
+	}
+
+	if apiClient.servers[0].Status != pritunl.ServerStatusOnline {
+		t.Error("expected the server to be started again")
+	}
+}
+
+func TestRestoreDryRun(t *testing.T) {
+	document := fixtureBackup(t)
+
+	apiClient := newMemoryClient()
+	r, out := restoreDocument(t, apiClient, document, true)
+
+	if apiClient.writes != 0 {
+		t.Errorf("expected the dry run to change nothing, got %d writes", apiClient.writes)
+	}
+
+	for _, line := range []string{
+		`! host "pritunl-1.example.com" doesn't exist, start Pritunl on it and restore again`,
+		`+ organization "Ops Team"`,
+		`+ user "Ops Team/alice"`,
+		`+ route "prod-vpn" 8.8.8.8/32`,
+		`+ attachment "prod-vpn" organization "Developers"`,
+		`! host "pritunl-1.example.com" attached to the server "prod-vpn" doesn't exist`,
+	} {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("expected %q in:\n%s", line, out)
+		}
+	}
+	if r.changes == 0 {
+		t.Error("expected changes in the dry run")
+	}
+}
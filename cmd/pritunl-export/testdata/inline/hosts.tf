@@ -0,0 +1,12 @@
+resource "pritunl_host" "pritunl-1" {
+  hostname           = "pritunl-1.example.com"
+  name               = "pritunl-1"
+  public_addr        = "203.0.113.10"
+  local_addr         = "10.0.0.10"
+  availability_group = "default"
+}
+
+import {
+  to = pritunl_host.pritunl-1
+  id = "5f00000000000000000000d1"
+}
@@ -0,0 +1,18 @@
+resource "pritunl_organization" "developers" {
+  name = "Developers"
+}
+
+import {
+  to = pritunl_organization.developers
+  id = "5f00000000000000000000a1"
+}
+
+resource "pritunl_organization" "ops_team" {
+  name     = "Ops Team"
+  auth_api = true
+}
+
+import {
+  to = pritunl_organization.ops_team
+  id = "5f00000000000000000000a2"
+}
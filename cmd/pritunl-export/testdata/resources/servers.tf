@@ -0,0 +1,94 @@
+resource "pritunl_server" "prod-vpn" {
+  name               = "prod-vpn"
+  protocol           = "udp"
+  port               = 1194
+  cipher             = "aes128"
+  hash               = "sha1"
+  network            = "192.168.235.0/24"
+  groups             = ["developers"]
+  dns_servers        = ["8.8.8.8"]
+  ping_interval      = 10
+  ping_timeout       = 60
+  max_clients        = 2000
+  replica_count      = 1
+  multi_device       = true
+  manage_attachments = false
+  status             = "online"
+}
+
+import {
+  to = pritunl_server.prod-vpn
+  id = "5f00000000000000000000c1"
+}
+
+resource "pritunl_route" "prod-vpn_10_0_0_0_16" {
+  server_id = pritunl_server.prod-vpn.id
+  network   = "10.0.0.0/16"
+  comment   = "office"
+  nat       = true
+}
+
+import {
+  to = pritunl_route.prod-vpn_10_0_0_0_16
+  id = "5f00000000000000000000c1/31302e302e302e302f3136"
+}
+
+resource "pritunl_route" "prod-vpn_8_8_8_8_32" {
+  server_id   = pritunl_server.prod-vpn.id
+  network     = "8.8.8.8/32"
+  nat         = false
+  net_gateway = true
+}
+
+import {
+  to = pritunl_route.prod-vpn_8_8_8_8_32
+  id = "5f00000000000000000000c1/382e382e382e382f3332"
+}
+
+resource "pritunl_server_organization_attachment" "prod-vpn_developers" {
+  server_id       = pritunl_server.prod-vpn.id
+  organization_id = pritunl_organization.developers.id
+}
+
+import {
+  to = pritunl_server_organization_attachment.prod-vpn_developers
+  id = "5f00000000000000000000c1/5f00000000000000000000a1"
+}
+
+resource "pritunl_server_organization_attachment" "prod-vpn_ops_team" {
+  server_id       = pritunl_server.prod-vpn.id
+  organization_id = pritunl_organization.ops_team.id
+}
+
+import {
+  to = pritunl_server_organization_attachment.prod-vpn_ops_team
+  id = "5f00000000000000000000c1/5f00000000000000000000a2"
+}
+
+resource "pritunl_server_host_attachment" "prod-vpn_pritunl-1" {
+  server_id = pritunl_server.prod-vpn.id
+  host_id   = pritunl_host.pritunl-1.id
+}
+
+import {
+  to = pritunl_server_host_attachment.prod-vpn_pritunl-1
+  id = "5f00000000000000000000c1/5f00000000000000000000d1"
+}
+
+resource "pritunl_server" "staging" {
+  name               = "staging"
+  protocol           = "tcp"
+  port               = 1195
+  cipher             = "aes256"
+  hash               = "sha256"
+  network            = "192.168.236.0/24"
+  network_wg         = "192.168.237.0/24"
+  port_wg            = 1196
+  manage_attachments = false
+  status             = "offline"
+}
+
+import {
+  to = pritunl_server.staging
+  id = "5f00000000000000000000c2"
+}
@@ -0,0 +1,40 @@
+resource "pritunl_user" "developers_alice" {
+  name            = "alice"
+  organization_id = pritunl_organization.developers.id
+  email           = "alice@example.com"
+  groups          = ["developers", "admins"]
+}
+
+import {
+  to = pritunl_user.developers_alice
+  id = "5f00000000000000000000a1/5f00000000000000000000b1"
+}
+
+resource "pritunl_user" "developers_bob" {
+  name            = "bob"
+  organization_id = pritunl_organization.developers.id
+  disabled        = true
+  port_forwarding = [{
+    dport    = "8080"
+    port     = "80"
+    protocol = "tcp"
+  }]
+}
+
+import {
+  to = pritunl_user.developers_bob
+  id = "5f00000000000000000000a1/5f00000000000000000000b2"
+}
+
+resource "pritunl_user" "ops_team_alice" {
+  name             = "alice"
+  organization_id  = pritunl_organization.ops_team.id
+  auth_type        = "local"
+  mac_addresses    = ["00:11:22:33:44:55"]
+  bypass_secondary = true
+}
+
+import {
+  to = pritunl_user.ops_team_alice
+  id = "5f00000000000000000000a2/5f00000000000000000000b4"
+}
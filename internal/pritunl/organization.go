@@ -1,6 +0,0 @@
-package pritunl
-
-type Organization struct {
-	ID   string `json:"id,omitempty"`
-	Name string `json:"name"`
-}
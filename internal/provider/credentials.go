@@ -0,0 +1,150 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/maulid7/terraform-provider-pritunl/pritunl"
+)
+
+const credentialsCommandTimeout = time.Minute
+
+// credentialsConfig holds the credential attributes of the provider, only one source can be
+// set for each of the token and the secret
+type credentialsConfig struct {
+	token              string
+	secret             string
+	tokenFile          string
+	secretFile         string
+	credentialsCommand string
+}
+
+// withEnvironment fills the sources missing from the configuration from the PRITUNL_TOKEN,
+// PRITUNL_SECRET, PRITUNL_TOKEN_FILE, PRITUNL_SECRET_FILE and PRITUNL_CREDENTIALS_COMMAND
+// environment variables. The command takes precedence over the other variables, and a
+// configured command or source isn't combined with the variables, so they can't conflict.
+func (c credentialsConfig) withEnvironment(getenv func(string) string) credentialsConfig {
+	if c.credentialsCommand != "" {
+		return c
+	}
+
+	if c.token == "" && c.secret == "" && c.tokenFile == "" && c.secretFile == "" {
+		if c.credentialsCommand = getenv("PRITUNL_CREDENTIALS_COMMAND"); c.credentialsCommand != "" {
+			return c
+		}
+	}
+
+	if c.token == "" && c.tokenFile == "" {
+		c.token = getenv("PRITUNL_TOKEN")
+		c.tokenFile = getenv("PRITUNL_TOKEN_FILE")
+	}
+	if c.secret == "" && c.secretFile == "" {
+		c.secret = getenv("PRITUNL_SECRET")
+		c.secretFile = getenv("PRITUNL_SECRET_FILE")
+	}
+
+	return c
+}
+
+// credentialsFunc returns the function reading the credentials from the configured sources.
+// Files and commands are read again on each call, so rotated credentials are picked up.
+func (c credentialsConfig) credentialsFunc() (pritunl.CredentialsFunc, error) {
+	if c.credentialsCommand != "" {
+		if c.token != "" || c.secret != "" || c.tokenFile != "" || c.secretFile != "" {
+			return nil, errors.New("credentials_command can't be combined with token, secret, token_file or secret_file")
+		}
+
+		return func() (string, string, error) {
+			return runCredentialsCommand(c.credentialsCommand)
+		}, nil
+	}
+
+	if c.token != "" && c.tokenFile != "" {
+		return nil, errors.New("only one of token or token_file can be set")
+	}
+	if c.secret != "" && c.secretFile != "" {
+		return nil, errors.New("only one of secret or secret_file can be set")
+	}
+	if c.token == "" && c.tokenFile == "" {
+		return nil, errors.New("one of token, token_file or credentials_command must be set")
+	}
+	if c.secret == "" && c.secretFile == "" {
+		return nil, errors.New("one of secret, secret_file or credentials_command must be set")
+	}
+
+	return func() (string, string, error) {
+		token, err := credentialValue(c.token, c.tokenFile, "token_file")
+		if err != nil {
+			return "", "", err
+		}
+
+		secret, err := credentialValue(c.secret, c.secretFile, "secret_file")
+		if err != nil {
+			return "", "", err
+		}
+
+		return token, secret, nil
+	}, nil
+}
+
+// credentialValue returns the value or the content of the file without the surrounding whitespace
+func credentialValue(value, path, attribute string) (string, error) {
+	if path == "" {
+		return value, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the %s: %w", attribute, err)
+	}
+
+	value = strings.TrimSpace(string(content))
+	if value == "" {
+		return "", fmt.Errorf("the %s %s is empty", attribute, path)
+	}
+
+	return value, nil
+}
+
+// runCredentialsCommand runs the command with the shell and parses the credentials it prints
+// as JSON, e.g. {"token": "...", "secret": "..."}. The output is never part of the errors.
+func runCredentialsCommand(command string) (string, string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), credentialsCommandTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+
+	// stderr may contain the credentials too, it's left out of the error
+	if err := cmd.Run(); err != nil {
+		return "", "", fmt.Errorf("credentials_command failed: %s", err)
+	}
+
+	var output struct {
+		Token  string `json:"token"`
+		Secret string `json:"secret"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		return "", "", errors.New(`credentials_command must print a JSON object with "token" and "secret"`)
+	}
+	if output.Token == "" || output.Secret == "" {
+		return "", "", errors.New(`credentials_command printed an empty "token" or "secret"`)
+	}
+
+	return output.Token, output.Secret, nil
+}
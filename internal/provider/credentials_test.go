@@ -0,0 +1,139 @@
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestCredentialsConfigInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		config credentialsConfig
+		err    string
+	}{
+		{"missing", credentialsConfig{}, "one of token, token_file or credentials_command must be set"},
+		{"missing secret", credentialsConfig{token: "token"}, "one of secret, secret_file or credentials_command must be set"},
+		{"token conflict", credentialsConfig{token: "token", tokenFile: "token.txt", secret: "secret"}, "only one of token or token_file"},
+		{"secret conflict", credentialsConfig{token: "token", secret: "secret", secretFile: "secret.txt"}, "only one of secret or secret_file"},
+		{"command conflict", credentialsConfig{secret: "secret", credentialsCommand: "vault-pritunl"}, "credentials_command can't be combined"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := test.config.credentialsFunc()
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("expected %q, got %v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestCredentialsEnvironment(t *testing.T) {
+	environment := map[string]string{
+		"PRITUNL_TOKEN":       "env-token",
+		"PRITUNL_SECRET":      "env-secret",
+		"PRITUNL_SECRET_FILE": "secret.txt",
+	}
+	getenv := func(name string) string { return environment[name] }
+
+	tests := []struct {
+		name     string
+		config   credentialsConfig
+		expected credentialsConfig
+	}{
+		{"unset", credentialsConfig{}, credentialsConfig{token: "env-token", secret: "env-secret", secretFile: "secret.txt"}},
+		{"configured command", credentialsConfig{credentialsCommand: "vault-pritunl"}, credentialsConfig{credentialsCommand: "vault-pritunl"}},
+		{"configured token", credentialsConfig{tokenFile: "token.txt"}, credentialsConfig{tokenFile: "token.txt", secret: "env-secret", secretFile: "secret.txt"}},
+		{"configured secret", credentialsConfig{secret: "secret"}, credentialsConfig{token: "env-token", secret: "secret"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if config := test.config.withEnvironment(getenv); config != test.expected {
+				t.Errorf("expected %+v, got %+v", test.expected, config)
+			}
+		})
+	}
+
+	// the command of the environment takes precedence over its token and secret
+	environment["PRITUNL_CREDENTIALS_COMMAND"] = "vault-pritunl"
+	if config := (credentialsConfig{}).withEnvironment(getenv); config != (credentialsConfig{credentialsCommand: "vault-pritunl"}) {
+		t.Errorf("expected the command only, got %+v", config)
+	}
+	if config := (credentialsConfig{token: "token"}).withEnvironment(getenv); config.credentialsCommand != "" {
+		t.Errorf("expected the configured token to ignore the command, got %+v", config)
+	}
+}
+
+func TestCredentialsFiles(t *testing.T) {
+	dir := t.TempDir()
+	secretFile := filepath.Join(dir, "secret")
+	if err := os.WriteFile(secretFile, []byte("secret-1\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	fn, err := credentialsConfig{token: "token", secretFile: secretFile}.credentialsFunc()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, secret, err := fn()
+	if err != nil || token != "token" || secret != "secret-1" {
+		t.Errorf("unexpected credentials %q %q: %v", token, secret, err)
+	}
+
+	// the rotated secret is read by the next call
+	if err := os.WriteFile(secretFile, []byte("secret-2"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, secret, _ = fn(); secret != "secret-2" {
+		t.Errorf("expected the rotated secret, got %q", secret)
+	}
+
+	if err := os.WriteFile(secretFile, []byte("\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = fn(); err == nil || !strings.Contains(err.Error(), "is empty") {
+		t.Errorf("expected an empty file error, got %v", err)
+	}
+}
+
+func TestCredentialsCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the commands use sh")
+	}
+
+	fn, err := credentialsConfig{credentialsCommand: `echo '{"token": "token", "secret": "secret"}'`}.credentialsFunc()
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, secret, err := fn()
+	if err != nil || token != "token" || secret != "secret" {
+		t.Errorf("unexpected credentials %q %q: %v", token, secret, err)
+	}
+
+	tests := []struct {
+		name    string
+		command string
+		err     string
+	}{
+		{"failure", "echo 'top-secret' >&2; exit 2", "credentials_command failed: exit status 2"},
+		{"invalid json", "echo 'token=top-secret'", "must print a JSON object"},
+		{"missing secret", `echo '{"token": "top-secret"}'`, `empty "token" or "secret"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, _, err := runCredentialsCommand(test.command)
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Fatalf("expected %q, got %v", test.err, err)
+			}
+			if strings.Contains(err.Error(), "top-secret") {
+				t.Errorf("the output leaked into the error: %s", err)
+			}
+		})
+	}
+}
@@ -3,93 +3,366 @@ package provider
 import (
 	"context"
 	"errors"
-	"github.com/maulid7/terraform-provider-pritunl/internal/pritunl"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/maulid7/terraform-provider-pritunl/pritunl"
 )
 
+var errHostNotFound = errors.New("could not find a host with specified parameters")
+
 func dataSourceHost() *schema.Resource {
+	hostSchema := dataSourceHostAttributes()
+
+	for key, filterSchema := range dataSourceHostFilterAttributes() {
+		hostSchema[key] = filterSchema
+	}
+
+	hostSchema["most_recent"] = &schema.Schema{
+		Description: "If more than one host matches, use the most recently registered one. Otherwise an error is returned",
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Default:     false,
+	}
+
+	hostSchema["wait_for"] = dataSourceHostWaitForSchema("Wait until a host matching the parameters appears, e.g. while a new instance registers in Pritunl")
+
 	return &schema.Resource{
 		Description: "Use this data source to get information about the Pritunl hosts.",
 		ReadContext: dataSourceHostRead,
-		Schema: map[string]*schema.Schema{
-			"id": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"hostname": {
-				Description: "Hostname",
-				Type:        schema.TypeString,
-				Required:    true,
-			},
-			"name": {
-				Description: "Name of host",
-				Type:        schema.TypeString,
-				Computed:    true,
-			},
-			"public_addr": {
-				Description: "Public IP address or domain name of the host",
-				Type:        schema.TypeString,
-				Computed:    true,
-			},
-			"public_addr6": {
-				Description: "Public IPv6 address or domain name of the host",
-				Type:        schema.TypeString,
-				Computed:    true,
-			},
-			"routed_subnet6": {
-				Description: "IPv6 subnet that is routed to the host",
-				Type:        schema.TypeString,
-				Computed:    true,
-			},
-			"routed_subnet6_wg": {
-				Description: "IPv6 WG subnet that is routed to the host",
-				Type:        schema.TypeString,
-				Computed:    true,
-			},
-			"local_addr": {
-				Description: "Local network address for server",
-				Type:        schema.TypeString,
-				Computed:    true,
-			},
-			"local_addr6": {
-				Description: "Local IPv6 network address for server",
-				Type:        schema.TypeString,
-				Computed:    true,
-			},
-			"availability_group": {
-				Description: "Availability group for host. Replicated servers will only be replicated to a group of hosts in the same availability group\"",
-				Type:        schema.TypeString,
-				Computed:    true,
-			},
-			"link_addr": {
-				Description: "IP address or domain used when linked servers connect to a linked server on this host",
-				Type:        schema.TypeString,
-				Computed:    true,
-			},
-			"sync_address": {
-				Description: "IP address or domain used by users when syncing configuration. This is needed when using a load balancer.",
-				Type:        schema.TypeString,
-				Computed:    true,
-			},
-			"status": {
-				Description: "Status of host",
-				Type:        schema.TypeString,
-				Computed:    true,
+		Schema:      hostSchema,
+	}
+}
+
+// Computed attributes of a host shared by the pritunl_host and pritunl_hosts data sources
+func dataSourceHostAttributes() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"id": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"hostname": {
+			Description: "Hostname",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+		"name": {
+			Description: "Name of host",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+		"public_addr": {
+			Description: "Public IP address or domain name of the host",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+		"public_addr6": {
+			Description: "Public IPv6 address or domain name of the host",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+		"routed_subnet6": {
+			Description: "IPv6 subnet that is routed to the host",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+		"routed_subnet6_wg": {
+			Description: "IPv6 WG subnet that is routed to the host",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+		"local_addr": {
+			Description: "Local network address for server",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+		"local_addr6": {
+			Description: "Local IPv6 network address for server",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+		"availability_group": {
+			Description: "Availability group for host. Replicated servers will only be replicated to a group of hosts in the same availability group\"",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+		"link_addr": {
+			Description: "IP address or domain used when linked servers connect to a linked server on this host",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+		"sync_address": {
+			Description: "IP address or domain used by users when syncing configuration. This is needed when using a load balancer.",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+		"status": {
+			Description: "Status of host",
+			Type:        schema.TypeString,
+			Computed:    true,
+		},
+	}
+}
+
+// Lookup arguments shared by the pritunl_host data source and the pritunl_hosts filter block
+func dataSourceHostFilterAttributes() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"id": {
+			Description: "ID of the host",
+			Type:        schema.TypeString,
+			Optional:    true,
+			Computed:    true,
+		},
+		"hostname": {
+			Description: "Hostname",
+			Type:        schema.TypeString,
+			Optional:    true,
+			Computed:    true,
+		},
+		"name": {
+			Description: "Name of host",
+			Type:        schema.TypeString,
+			Optional:    true,
+			Computed:    true,
+		},
+		"name_regex": {
+			Description:  "Regular expression the name of host has to match",
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.StringIsValidRegExp,
+		},
+		"availability_group": {
+			Description: "Availability group for host. Replicated servers will only be replicated to a group of hosts in the same availability group",
+			Type:        schema.TypeString,
+			Optional:    true,
+			Computed:    true,
+		},
+		"status": {
+			Description:  "Status of host",
+			Type:         schema.TypeString,
+			Optional:     true,
+			Computed:     true,
+			ValidateFunc: validation.StringInSlice([]string{pritunl.ServerStatusOnline, pritunl.ServerStatusOffline}, false),
+		},
+	}
+}
+
+func dataSourceHostWaitForSchema(description string) *schema.Schema {
+	return &schema.Schema{
+		Description: description,
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"timeout": {
+					Description: "How long to wait, e.g. 30s or 5m",
+					Type:        schema.TypeString,
+					Optional:    true,
+					Default:     "5m",
+					ValidateFunc: func(i interface{}, s string) ([]string, []error) {
+						if _, err := time.ParseDuration(i.(string)); err != nil {
+							return nil, []error{fmt.Errorf("expected %s to be a duration, got %s: %s", s, i, err)}
+						}
+						return nil, nil
+					},
+				},
+				"require_status": {
+					Description:  "Status the matching hosts have to reach",
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: validation.StringInSlice([]string{pritunl.ServerStatusOnline, pritunl.ServerStatusOffline}, false),
+				},
 			},
 		},
 	}
 }
 
-func dataSourceHostRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	hostname := d.Get("hostname")
-	filterFunction := func(host pritunl.Host) bool {
-		return host.Hostname == hostname
+type hostWaiter struct {
+	timeout       time.Duration
+	requireStatus string
+}
+
+func expandHostWaiter(v interface{}) *hostWaiter {
+	waitFor, ok := v.([]interface{})
+	if !ok || len(waitFor) == 0 || waitFor[0] == nil {
+		return nil
+	}
+
+	attributes := waitFor[0].(map[string]interface{})
+	timeout, _ := time.ParseDuration(attributes["timeout"].(string))
+
+	return &hostWaiter{
+		timeout:       timeout,
+		requireStatus: attributes["require_status"].(string),
+	}
+}
+
+// wait polls the hosts with backoff until read returns no error or the timeout is reached
+func (w *hostWaiter) wait(ctx context.Context, apiClient pritunl.Client, read func(hosts []pritunl.Host) (bool, error)) error {
+	var lastErr error
+
+	err := resource.RetryContext(ctx, w.timeout, func() *resource.RetryError {
+		hosts, err := apiClient.GetHosts()
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+
+		retryable, err := read(hosts)
+		if err == nil {
+			return nil
+		}
+
+		if !retryable {
+			return resource.NonRetryableError(err)
+		}
+
+		lastErr = err
+		return resource.RetryableError(err)
+	})
+
+	var timeoutErr *resource.TimeoutError
+	if errors.As(err, &timeoutErr) && lastErr != nil {
+		return fmt.Errorf("gave up waiting after %s: %w", w.timeout, lastErr)
 	}
 
-	host, err := filterHosts(meta, filterFunction)
+	return err
+}
+
+type hostFilter struct {
+	id                string
+	hostname          string
+	name              string
+	nameRegex         *regexp.Regexp
+	availabilityGroup string
+	status            string
+}
+
+func newHostFilter(attributes map[string]interface{}) (hostFilter, error) {
+	filter := hostFilter{}
+
+	filter.id, _ = attributes["id"].(string)
+	filter.hostname, _ = attributes["hostname"].(string)
+	filter.name, _ = attributes["name"].(string)
+	filter.availabilityGroup, _ = attributes["availability_group"].(string)
+	filter.status, _ = attributes["status"].(string)
+
+	if nameRegex, _ := attributes["name_regex"].(string); nameRegex != "" {
+		re, err := regexp.Compile(nameRegex)
+		if err != nil {
+			return filter, fmt.Errorf("invalid name_regex %s: %s", nameRegex, err)
+		}
+		filter.nameRegex = re
+	}
+
+	return filter, nil
+}
+
+func (f hostFilter) matches(host pritunl.Host) bool {
+	if f.id != "" && host.ID != f.id {
+		return false
+	}
+	if f.hostname != "" && host.Hostname != f.hostname {
+		return false
+	}
+	if f.name != "" && host.Name != f.name {
+		return false
+	}
+	if f.nameRegex != nil && !f.nameRegex.MatchString(host.Name) {
+		return false
+	}
+	if f.availabilityGroup != "" && host.AvailabilityGroup != f.availabilityGroup {
+		return false
+	}
+	if f.status != "" && host.Status != f.status {
+		return false
+	}
+
+	return true
+}
+
+func (f hostFilter) String() string {
+	conditions := make([]string, 0)
+
+	if f.id != "" {
+		conditions = append(conditions, fmt.Sprintf("an id %s", f.id))
+	}
+	if f.hostname != "" {
+		conditions = append(conditions, fmt.Sprintf("a hostname %s", f.hostname))
+	}
+	if f.name != "" {
+		conditions = append(conditions, fmt.Sprintf("a name %s", f.name))
+	}
+	if f.nameRegex != nil {
+		conditions = append(conditions, fmt.Sprintf("a name matching %s", f.nameRegex))
+	}
+	if f.availabilityGroup != "" {
+		conditions = append(conditions, fmt.Sprintf("an availability group %s", f.availabilityGroup))
+	}
+	if f.status != "" {
+		conditions = append(conditions, fmt.Sprintf("a status %s", f.status))
+	}
+
+	if len(conditions) == 0 {
+		return "any parameters"
+	}
+
+	return strings.Join(conditions, ", ")
+}
+
+func dataSourceHostRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	apiClient := requestClient(ctx, meta)
+
+	filter, err := newHostFilter(map[string]interface{}{
+		"id":                 d.Get("id"),
+		"hostname":           d.Get("hostname"),
+		"name":               d.Get("name"),
+		"name_regex":         d.Get("name_regex"),
+		"availability_group": d.Get("availability_group"),
+		"status":             d.Get("status"),
+	})
 	if err != nil {
-		return diag.Errorf("could not find host with a hostname %s. Previous error message: %v", hostname, err)
+		return diag.FromErr(err)
+	}
+
+	mostRecent := d.Get("most_recent").(bool)
+
+	var host pritunl.Host
+	selectMatchingHost := func(hosts []pritunl.Host) error {
+		host, err = selectHost(hosts, filter, mostRecent)
+		return err
+	}
+
+	if waiter := expandHostWaiter(d.Get("wait_for")); waiter != nil {
+		err = waiter.wait(ctx, apiClient, func(hosts []pritunl.Host) (bool, error) {
+			if err := selectMatchingHost(hosts); err != nil {
+				return !errors.Is(err, errHostNotFound), err
+			}
+
+			if waiter.requireStatus != "" && host.Status != waiter.requireStatus {
+				return true, fmt.Errorf("the host %s has a status %s, expected %s", host.Hostname, host.Status, waiter.requireStatus)
+			}
+
+			return false, nil
+		})
+	} else {
+		var hosts []pritunl.Host
+		hosts, err = apiClient.GetHosts()
+		if err == nil {
+			err = selectMatchingHost(hosts)
+		}
+	}
+
+	if err != nil {
+		return diag.Errorf("could not find host with %s. Previous error message: %v", filter, err)
 	}
 
 	d.SetId(host.ID)
@@ -109,9 +382,54 @@ func dataSourceHostRead(_ context.Context, d *schema.ResourceData, meta interfac
 	return nil
 }
 
-func filterHosts(meta interface{}, test func(host pritunl.Host) bool) (pritunl.Host, error) {
-	apiClient := meta.(pritunl.Client)
+// selectHost returns the single host matching the filter, or the most recently registered one if mostRecent is set
+func selectHost(hosts []pritunl.Host, filter hostFilter, mostRecent bool) (pritunl.Host, error) {
+	matchedHosts := filterHostsList(hosts, filter.matches)
+
+	if len(matchedHosts) == 0 {
+		return pritunl.Host{}, errHostNotFound
+	}
+
+	if len(matchedHosts) > 1 {
+		if !mostRecent {
+			return pritunl.Host{}, fmt.Errorf("found %d hosts with specified parameters, use more specific parameters or set most_recent = true", len(matchedHosts))
+		}
+
+		sort.SliceStable(matchedHosts, func(i, j int) bool {
+			return hostRegistrationTime(matchedHosts[i]) > hostRegistrationTime(matchedHosts[j])
+		})
+	}
+
+	return matchedHosts[0], nil
+}
+
+// Host IDs are MongoDB object IDs, which start with the creation timestamp in seconds
+func hostRegistrationTime(host pritunl.Host) int64 {
+	if len(host.ID) < 8 {
+		return 0
+	}
+
+	timestamp, err := strconv.ParseInt(host.ID[:8], 16, 64)
+	if err != nil {
+		return 0
+	}
+
+	return timestamp
+}
+
+func filterHostsList(hosts []pritunl.Host, test func(host pritunl.Host) bool) []pritunl.Host {
+	result := make([]pritunl.Host, 0)
+
+	for _, host := range hosts {
+		if test(host) {
+			result = append(result, host)
+		}
+	}
+
+	return result
+}
 
+func filterHosts(apiClient pritunl.Client, test func(host pritunl.Host) bool) (pritunl.Host, error) {
 	hosts, err := apiClient.GetHosts()
 
 	if err != nil {
@@ -124,5 +442,5 @@ func filterHosts(meta interface{}, test func(host pritunl.Host) bool) (pritunl.H
 		}
 	}
 
-	return pritunl.Host{}, errors.New("could not find a host with specified parameters")
+	return pritunl.Host{}, errHostNotFound
 }
@@ -2,9 +2,11 @@ package provider
 
 import (
 	"fmt"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"regexp"
 	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/maulid7/terraform-provider-pritunl/pritunl"
 )
 
 func TestDataSourceHost(t *testing.T) {
@@ -27,6 +29,102 @@ func TestDataSourceHost(t *testing.T) {
 	})
 }
 
+func TestDataSourceHostFilters(t *testing.T) {
+	existsHostname := "pritunl.local"
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() {},
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testPritunlHostFilterConfig(`name_regex = ".*"
+	status = "online"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.pritunl_host.test", "hostname", existsHostname),
+					resource.TestCheckResourceAttr("data.pritunl_host.test", "status", "online"),
+				),
+			},
+			{
+				Config:      testPritunlHostFilterConfig(`availability_group = "not-exist-group"`),
+				ExpectError: regexp.MustCompile("could not find host with an availability group not-exist-group"),
+			},
+			{
+				Config: testPritunlHostFilterConfig(`hostname = "pritunl.local"
+	wait_for {
+		timeout        = "1m"
+		require_status = "online"
+	}`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.pritunl_host.test", "status", "online"),
+				),
+			},
+			{
+				Config: testPritunlHostFilterConfig(`hostname = "not-exist-hostname"
+	wait_for {
+		timeout = "5s"
+	}`),
+				ExpectError: regexp.MustCompile("gave up waiting after 5s: could not find a host with specified parameters"),
+			},
+		},
+	})
+}
+
+func TestSelectHost(t *testing.T) {
+	hosts := []pritunl.Host{
+		{ID: "5f000000aaaaaaaaaaaaaaaa", Name: "vpn-a", Hostname: "a.local", AvailabilityGroup: "default", Status: "online"},
+		{ID: "60000000bbbbbbbbbbbbbbbb", Name: "vpn-b", Hostname: "b.local", AvailabilityGroup: "default", Status: "offline"},
+		{ID: "61000000cccccccccccccccc", Name: "db-c", Hostname: "c.local", AvailabilityGroup: "eu", Status: "online"},
+	}
+
+	testCases := []struct {
+		name        string
+		filter      map[string]interface{}
+		mostRecent  bool
+		expectedId  string
+		expectedErr string
+	}{
+		{"by id", map[string]interface{}{"id": "60000000bbbbbbbbbbbbbbbb"}, false, "60000000bbbbbbbbbbbbbbbb", ""},
+		{"by hostname", map[string]interface{}{"hostname": "c.local"}, false, "61000000cccccccccccccccc", ""},
+		{"by name", map[string]interface{}{"name": "vpn-a"}, false, "5f000000aaaaaaaaaaaaaaaa", ""},
+		{"by availability group and status", map[string]interface{}{"availability_group": "default", "status": "offline"}, false, "60000000bbbbbbbbbbbbbbbb", ""},
+		{"by name regex", map[string]interface{}{"name_regex": "^db-"}, false, "61000000cccccccccccccccc", ""},
+		{"multiple hosts", map[string]interface{}{"name_regex": "^vpn-"}, false, "", "found 2 hosts"},
+		{"most recent of multiple hosts", map[string]interface{}{"name_regex": "^vpn-"}, true, "60000000bbbbbbbbbbbbbbbb", ""},
+		{"no hosts", map[string]interface{}{"status": "offline", "availability_group": "eu"}, false, "", "could not find a host with specified parameters"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filter, err := newHostFilter(tc.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			host, err := selectHost(hosts, filter, tc.mostRecent)
+			if tc.expectedErr != "" {
+				if err == nil || !regexp.MustCompile(tc.expectedErr).MatchString(err.Error()) {
+					t.Fatalf("expected an error matching %q, got %v", tc.expectedErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if host.ID != tc.expectedId {
+				t.Errorf("expected host %s, got %s", tc.expectedId, host.ID)
+			}
+		})
+	}
+}
+
+func testPritunlHostFilterConfig(filter string) string {
+	return fmt.Sprintf(`
+data "pritunl_host" "test" {
+	%[1]s
+}
+`, filter)
+}
+
 func testPritunlHostSimpleConfig(name string) string {
 	return fmt.Sprintf(`
 data "pritunl_host" "test" {
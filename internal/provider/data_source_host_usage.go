@@ -0,0 +1,95 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/maulid7/terraform-provider-pritunl/pritunl"
+)
+
+func dataSourceHostUsage() *schema.Resource {
+	return &schema.Resource{
+		Description: "Use this data source to get the CPU and memory usage of a Pritunl host.",
+		ReadContext: dataSourceHostUsageRead,
+		Schema: map[string]*schema.Schema{
+			"host_id": {
+				Description: "ID of the host",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"period": statisticsPeriodSchema(),
+			"cpu":    statisticsSeriesSchema("CPU usage samples of the host"),
+			"mem":    statisticsSeriesSchema("Memory usage samples of the host"),
+		},
+	}
+}
+
+func dataSourceHostUsageRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	apiClient := requestClient(ctx, meta)
+
+	hostId := d.Get("host_id").(string)
+	period := d.Get("period").(string)
+
+	usage, err := apiClient.GetHostUsage(hostId, period)
+	if err != nil {
+		return diag.Errorf("could not get usage of the host %s. Previous error message: %v", hostId, err)
+	}
+
+	if err = d.Set("cpu", flattenStatisticsPoints(usage.Cpu)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err = d.Set("mem", flattenStatisticsPoints(usage.Mem)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(hostId + "-" + period)
+
+	return nil
+}
+
+func statisticsPeriodSchema() *schema.Schema {
+	return &schema.Schema{
+		Description:  "Period of the samples, one of 1m, 5m, 30m, 2h or 1d",
+		Type:         schema.TypeString,
+		Required:     true,
+		ValidateFunc: validation.StringInSlice(pritunl.StatisticsPeriods, false),
+	}
+}
+
+func statisticsSeriesSchema(description string) *schema.Schema {
+	return &schema.Schema{
+		Description: description,
+		Type:        schema.TypeList,
+		Computed:    true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"timestamp": {
+					Description: "Unix timestamp of the sample",
+					Type:        schema.TypeInt,
+					Computed:    true,
+				},
+				"value": {
+					Description: "Value of the sample",
+					Type:        schema.TypeFloat,
+					Computed:    true,
+				},
+			},
+		},
+	}
+}
+
+func flattenStatisticsPoints(points []pritunl.StatisticsPoint) []interface{} {
+	result := make([]interface{}, 0, len(points))
+
+	for _, point := range points {
+		result = append(result, map[string]interface{}{
+			"timestamp": int(point.Timestamp),
+			"value":     point.Value,
+		})
+	}
+
+	return result
+}
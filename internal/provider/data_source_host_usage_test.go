@@ -0,0 +1,43 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestDataSourceHostUsage(t *testing.T) {
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck:          func() {},
+		ProviderFactories: providerFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testPritunlHostUsageConfig("1m"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.pritunl_host_usage.test", "host_id", "data.pritunl_host.test", "id"),
+					resource.TestCheckResourceAttrSet("data.pritunl_host_usage.test", "cpu.#"),
+					resource.TestCheckResourceAttrSet("data.pritunl_host_usage.test", "mem.#"),
+				),
+			},
+			{
+				Config:      testPritunlHostUsageConfig("1w"),
+				ExpectError: regexp.MustCompile(`expected period to be one of \[1m 5m 30m 2h 1d\]`),
+			},
+		},
+	})
+}
+
+func testPritunlHostUsageConfig(period string) string {
+	return fmt.Sprintf(`
+data "pritunl_host" "test" {
+  hostname = "pritunl.local"
+}
+
+data "pritunl_host_usage" "test" {
+  host_id = data.pritunl_host.test.id
+  period  = "%[1]s"
+}
+`, period)
+}
@@ -2,46 +2,116 @@ package provider
 
 import (
 	"context"
-	"github.com/maulid7/terraform-provider-pritunl/internal/pritunl"
+	"fmt"
+
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/maulid7/terraform-provider-pritunl/pritunl"
 )
 
 func dataSourceHosts() *schema.Resource {
+	filterSchema := dataSourceHostFilterAttributes()
+	for _, attributeSchema := range filterSchema {
+		attributeSchema.Computed = false
+	}
+
 	return &schema.Resource{
 		Description: "Use this data source to get a list of the Pritunl hosts.",
 		ReadContext: dataSourceHostsRead,
 		Schema: map[string]*schema.Schema{
+			"filter": {
+				Description: "Return only the hosts matching all of the specified parameters.",
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: filterSchema,
+				},
+			},
+			"wait_for": dataSourceHostWaitForSchema("Wait until at least one host matches the filter and all matching hosts reach the required status"),
 			"hosts": {
 				Description: "A list of the Pritunl hosts resources.",
 				Type:        schema.TypeList,
 				Computed:    true,
 				Elem: &schema.Resource{
-					Schema: dataSourceHost().Schema,
+					Schema: dataSourceHostAttributes(),
+				},
+			},
+			"ids": {
+				Description: "A list of IDs of the Pritunl hosts.",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
 				},
 			},
 		},
 	}
 }
 
-func dataSourceHostsRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	apiClient := meta.(pritunl.Client)
+func dataSourceHostsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	apiClient := requestClient(ctx, meta)
+
+	filterAttributes := make(map[string]interface{})
+	if filters := d.Get("filter").([]interface{}); len(filters) > 0 && filters[0] != nil {
+		filterAttributes = filters[0].(map[string]interface{})
+	}
+
+	filter, err := newHostFilter(filterAttributes)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var matchedHosts []pritunl.Host
+	matchHosts := func(hosts []pritunl.Host) {
+		matchedHosts = filterHostsList(hosts, filter.matches)
+	}
+
+	if waiter := expandHostWaiter(d.Get("wait_for")); waiter != nil {
+		err = waiter.wait(ctx, apiClient, func(hosts []pritunl.Host) (bool, error) {
+			matchHosts(hosts)
+			if len(matchedHosts) == 0 {
+				return true, fmt.Errorf("no host matches %s", filter)
+			}
+
+			if waiter.requireStatus != "" {
+				for _, host := range matchedHosts {
+					if host.Status != waiter.requireStatus {
+						return true, fmt.Errorf("the host %s has a status %s, expected %s", host.Hostname, host.Status, waiter.requireStatus)
+					}
+				}
+			}
+
+			return false, nil
+		})
+	} else {
+		var hosts []pritunl.Host
+		hosts, err = apiClient.GetHosts()
+		if err == nil {
+			matchHosts(hosts)
+		}
+	}
 
-	hosts, err := apiClient.GetHosts()
 	if err != nil {
 		return diag.Errorf("could not find any host. Previous error message: %v", err)
 	}
 
 	var resultHosts []interface{}
+	resultIds := make([]string, 0)
 
-	for _, host := range hosts {
+	for _, host := range matchedHosts {
 		resultHosts = append(resultHosts, flattenHost(&host))
+		resultIds = append(resultIds, host.ID)
 	}
 
 	if err = d.Set("hosts", resultHosts); err != nil {
 		return diag.FromErr(err)
 	}
 
+	if err = d.Set("ids", resultIds); err != nil {
+		return diag.FromErr(err)
+	}
+
 	d.SetId("hosts")
 
 	return nil
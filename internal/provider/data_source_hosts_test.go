@@ -3,6 +3,7 @@ package provider
 import (
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"regexp"
 	"testing"
 )
 
@@ -17,6 +18,38 @@ func TestDataSourceHosts(t *testing.T) {
 					resource.TestCheckOutput("num_hosts", "1"),
 				),
 			},
+			{
+				Config: testPritunlHostsFilterConfig("online"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.pritunl_hosts.test", "hosts.#", "1"),
+					resource.TestCheckResourceAttr("data.pritunl_hosts.test", "ids.#", "1"),
+				),
+			},
+			{
+				Config: testPritunlHostsFilterConfig("offline"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.pritunl_hosts.test", "hosts.#", "0"),
+					resource.TestCheckResourceAttr("data.pritunl_hosts.test", "ids.#", "0"),
+				),
+			},
+			{
+				Config: testPritunlHostsIdFilterConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.pritunl_hosts.test", "hosts.#", "1"),
+					resource.TestCheckResourceAttr("data.pritunl_hosts.test", "hosts.0.hostname", "pritunl.local"),
+					resource.TestCheckResourceAttrPair("data.pritunl_hosts.test", "ids.0", "data.pritunl_host.main", "id"),
+				),
+			},
+			{
+				Config: testPritunlHostsWaitForConfig("online"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.pritunl_hosts.test", "hosts.#", "1"),
+				),
+			},
+			{
+				Config:      testPritunlHostsWaitForConfig("offline"),
+				ExpectError: regexp.MustCompile("gave up waiting after 5s: the host pritunl.local has a status online, expected offline"),
+			},
 		},
 	})
 }
@@ -30,3 +63,42 @@ output "num_hosts" {
 }
 `)
 }
+
+func testPritunlHostsFilterConfig(status string) string {
+	return fmt.Sprintf(`
+data "pritunl_hosts" "test" {
+  filter {
+    status = "%[1]s"
+  }
+}
+`, status)
+}
+
+func testPritunlHostsIdFilterConfig() string {
+	return fmt.Sprintf(`
+data "pritunl_host" "main" {
+  hostname = "pritunl.local"
+}
+
+data "pritunl_hosts" "test" {
+  filter {
+    id = data.pritunl_host.main.id
+  }
+}
+`)
+}
+
+func testPritunlHostsWaitForConfig(status string) string {
+	return fmt.Sprintf(`
+data "pritunl_hosts" "test" {
+  filter {
+    hostname = "pritunl.local"
+  }
+
+  wait_for {
+    timeout        = "5s"
+    require_status = "%[1]s"
+  }
+}
+`, status)
+}
@@ -0,0 +1,62 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceServerBandwidth() *schema.Resource {
+	return &schema.Resource{
+		Description: "Use this data source to get the bandwidth used by a Pritunl server.",
+		ReadContext: dataSourceServerBandwidthRead,
+		Schema: map[string]*schema.Schema{
+			"server_id": {
+				Description: "ID of the server",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"period":   statisticsPeriodSchema(),
+			"received": statisticsSeriesSchema("Bytes received by the server per sample"),
+			"sent":     statisticsSeriesSchema("Bytes sent by the server per sample"),
+			"received_total": {
+				Description: "Total bytes received by the server over the period",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+			"sent_total": {
+				Description: "Total bytes sent by the server over the period",
+				Type:        schema.TypeInt,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataSourceServerBandwidthRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	apiClient := requestClient(ctx, meta)
+
+	serverId := d.Get("server_id").(string)
+	period := d.Get("period").(string)
+
+	bandwidth, err := apiClient.GetServerBandwidth(serverId, period)
+	if err != nil {
+		return diag.Errorf("could not get bandwidth of the server %s. Previous error message: %v", serverId, err)
+	}
+
+	if err = d.Set("received", flattenStatisticsPoints(bandwidth.Received)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err = d.Set("sent", flattenStatisticsPoints(bandwidth.Sent)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("received_total", int(bandwidth.ReceivedTotal))
+	d.Set("sent_total", int(bandwidth.SentTotal))
+
+	d.SetId(serverId + "-" + period)
+
+	return nil
+}
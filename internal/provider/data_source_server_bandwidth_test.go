@@ -0,0 +1,41 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestDataSourceServerBandwidth(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { preCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testPritunlServerDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testPritunlServerBandwidthConfig("tfacc-server-bandwidth", "5m"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.pritunl_server_bandwidth.test", "server_id", "pritunl_server.test", "id"),
+					resource.TestCheckResourceAttrSet("data.pritunl_server_bandwidth.test", "received.#"),
+					resource.TestCheckResourceAttrSet("data.pritunl_server_bandwidth.test", "sent.#"),
+					resource.TestCheckResourceAttr("data.pritunl_server_bandwidth.test", "received_total", "0"),
+					resource.TestCheckResourceAttr("data.pritunl_server_bandwidth.test", "sent_total", "0"),
+				),
+			},
+		},
+	})
+}
+
+func testPritunlServerBandwidthConfig(name, period string) string {
+	return fmt.Sprintf(`
+resource "pritunl_server" "test" {
+  name = "%[1]s"
+}
+
+data "pritunl_server_bandwidth" "test" {
+  server_id = pritunl_server.test.id
+  period    = "%[2]s"
+}
+`, name, period)
+}
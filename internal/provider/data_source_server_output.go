@@ -0,0 +1,74 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceServerOutput() *schema.Resource {
+	return &schema.Resource{
+		Description: "Use this data source to get the output of a Pritunl server for troubleshooting.",
+		ReadContext: dataSourceServerOutputRead,
+		Schema: map[string]*schema.Schema{
+			"server_id": {
+				Description: "ID of the server",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"lines": {
+				Description:  "Return only the last lines of the output. All lines are returned by default",
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"output": {
+				Description: "Output lines of the server",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"link_output": {
+				Description: "Output lines of the server links",
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+func dataSourceServerOutputRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	apiClient := requestClient(ctx, meta)
+
+	serverId := d.Get("server_id").(string)
+	lines := d.Get("lines").(int)
+
+	output, err := apiClient.GetServerOutput(serverId)
+	if err != nil {
+		return diag.Errorf("could not get output of the server %s. Previous error message: %v", serverId, err)
+	}
+
+	linkOutput, err := apiClient.GetServerLinkOutput(serverId)
+	if err != nil {
+		return diag.Errorf("could not get link output of the server %s. Previous error message: %v", serverId, err)
+	}
+
+	if err = d.Set("output", tailLines(output.Output, lines)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err = d.Set("link_output", tailLines(linkOutput.Output, lines)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(serverId)
+
+	return nil
+}
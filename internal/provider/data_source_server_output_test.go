@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/maulid7/terraform-provider-pritunl/pritunl"
+)
+
+func TestDataSourceServerOutput(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { preCheck(t) },
+		ProviderFactories: providerFactories,
+		CheckDestroy:      testPritunlServerDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testPritunlServerOutputConfig("tfacc-server-output"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.pritunl_server_output.test", "id", "pritunl_server.test", "id"),
+					resource.TestCheckResourceAttrSet("data.pritunl_server_output.test", "output.#"),
+					resource.TestCheckResourceAttrSet("data.pritunl_server_output.test", "link_output.#"),
+				),
+			},
+		},
+	})
+}
+
+// startFailingClient fails to start servers and returns a fixed server output
+type startFailingClient struct {
+	pritunl.Client
+	output    []string
+	outputErr error
+}
+
+func (c startFailingClient) StartServer(serverId string) error {
+	return errors.New("Non-200 response on starting the server")
+}
+
+func (c startFailingClient) GetServerOutput(serverId string) (*pritunl.ServerOutput, error) {
+	if c.outputErr != nil {
+		return nil, c.outputErr
+	}
+	return &pritunl.ServerOutput{ID: serverId, Output: c.output}, nil
+}
+
+func TestStartServerDiagnostics(t *testing.T) {
+	var output []string
+	for i := 1; i <= 30; i++ {
+		output = append(output, "line "+strconv.Itoa(i))
+	}
+
+	diags := startServer(startFailingClient{output: output}, "server")
+	if !diags.HasError() {
+		t.Fatal("expected an error diagnostic")
+	}
+
+	detail := diags[0].Detail
+	if !strings.HasPrefix(detail, "Last 20 lines of the server output:\nline 11\n") || !strings.HasSuffix(detail, "\nline 30") {
+		t.Errorf("unexpected detail: %q", detail)
+	}
+
+	diags = startServer(startFailingClient{outputErr: errors.New("connection refused")}, "server")
+	if detail := diags[0].Detail; detail != "Could not get the server output: connection refused" {
+		t.Errorf("unexpected detail: %q", detail)
+	}
+}
+
+func testPritunlServerOutputConfig(name string) string {
+	return fmt.Sprintf(`
+resource "pritunl_server" "test" {
+  name = "%[1]s"
+}
+
+data "pritunl_server_output" "test" {
+  server_id = pritunl_server.test.id
+  lines     = 10
+}
+`, name)
+}
@@ -0,0 +1,74 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/maulid7/terraform-provider-pritunl/pritunl"
+)
+
+const healthCheckTimeout = 10 * time.Second
+
+// endpoints returns the API URLs of the url and urls attributes without duplicates
+func endpoints(url string, urls []string) ([]string, error) {
+	var result []string
+	seen := map[string]bool{}
+
+	for _, endpoint := range append([]string{url}, urls...) {
+		if endpoint == "" || seen[endpoint] {
+			continue
+		}
+		seen[endpoint] = true
+		result = append(result, endpoint)
+	}
+
+	if len(result) == 0 {
+		return nil, errors.New("one of url or urls must be set")
+	}
+
+	return result, nil
+}
+
+// orderByHealth checks the endpoints concurrently with TestApiCall and moves the failing ones
+// to the end, keeping the configured order otherwise. The credentials are read once for all the
+// checks, so a credentials_command isn't run for each endpoint.
+func orderByHealth(ctx context.Context, endpoints []string, credentialsFunc pritunl.CredentialsFunc, opts ...pritunl.Option) []string {
+	token, secret, err := credentialsFunc()
+	if err != nil {
+		tflog.Debug(ctx, "Skipping the health checks of the API URLs", map[string]interface{}{"error": err.Error()})
+		return endpoints
+	}
+	opts = append([]pritunl.Option{pritunl.WithTimeout(healthCheckTimeout), pritunl.WithCredentials(token, secret)}, opts...)
+
+	healthy := make([]bool, len(endpoints))
+
+	var wg sync.WaitGroup
+	for i, endpoint := range endpoints {
+		wg.Add(1)
+		go func(i int, endpoint string) {
+			defer wg.Done()
+
+			apiClient := pritunl.NewClient(endpoint, opts...)
+			if err := apiClient.WithContext(ctx).TestApiCall(); err != nil {
+				tflog.Debug(ctx, "API URL failed the health check", map[string]interface{}{"endpoint": endpoint, "error": err.Error()})
+				return
+			}
+			healthy[i] = true
+		}(i, endpoint)
+	}
+	wg.Wait()
+
+	var ordered, failing []string
+	for i, endpoint := range endpoints {
+		if healthy[i] {
+			ordered = append(ordered, endpoint)
+		} else {
+			failing = append(failing, endpoint)
+		}
+	}
+
+	return append(ordered, failing...)
+}
@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+func TestEndpoints(t *testing.T) {
+	urls, err := endpoints("https://vpn1", []string{"https://vpn2", "https://vpn1", "https://vpn3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"https://vpn1", "https://vpn2", "https://vpn3"}; !reflect.DeepEqual(urls, expected) {
+		t.Errorf("expected %q, got %q", expected, urls)
+	}
+
+	urls, err = endpoints("", []string{"https://vpn2"})
+	if err != nil || !reflect.DeepEqual(urls, []string{"https://vpn2"}) {
+		t.Errorf("expected the urls only, got %q: %v", urls, err)
+	}
+
+	if _, err = endpoints("", nil); err == nil {
+		t.Error("expected an error without URLs")
+	}
+}
+
+func TestOrderByHealth(t *testing.T) {
+	newHost := func(status int) string {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+		}))
+		t.Cleanup(server.Close)
+
+		return server.URL
+	}
+
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	patching := newHost(http.StatusServiceUnavailable)
+	first := newHost(http.StatusOK)
+	second := newHost(http.StatusOK)
+
+	var reads int32
+	credentialsFunc := func() (string, string, error) {
+		atomic.AddInt32(&reads, 1)
+		return "token", "secret", nil
+	}
+
+	urls := orderByHealth(context.Background(), []string{down.URL, first, patching, second}, credentialsFunc)
+	if expected := []string{first, second, down.URL, patching}; !reflect.DeepEqual(urls, expected) {
+		t.Errorf("expected %q, got %q", expected, urls)
+	}
+	if reads != 1 {
+		t.Errorf("expected the credentials to be read once, got %d reads", reads)
+	}
+
+	failing := func() (string, string, error) {
+		return "", "", errors.New("credentials_command failed")
+	}
+	if urls := orderByHealth(context.Background(), []string{down.URL, first}, failing); !reflect.DeepEqual(urls, []string{down.URL, first}) {
+		t.Errorf("expected the configured order without credentials, got %q", urls)
+	}
+}
@@ -0,0 +1,200 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/maulid7/terraform-provider-pritunl/pritunl"
+)
+
+// parseImportId splits an import ID into one value per kind.
+//
+// Two formats are supported. The name-based one prefixes every value with its kind and
+// separates the values with "/", e.g. org:Developers/user:alice or
+// server:prod-vpn/route:10.0.0.0/16, a value may contain "/" as only "/kind:" separates
+// the values. The ID-based one lists the values in the order of the kinds separated with
+// "/", or with "-" as in the formats supported before, e.g. ${orgId}/${userId}.
+//
+// Both formats accept an ID or a name for each value, the values are resolved with the
+// resolve* functions.
+func parseImportId(id string, kinds ...string) ([]string, error) {
+	if strings.HasPrefix(id, kinds[0]+":") {
+		return parseNamedImportId(id, kinds)
+	}
+
+	separator := "/"
+	if !strings.Contains(id, separator) {
+		separator = "-"
+	}
+
+	values := strings.SplitN(id, separator, len(kinds))
+	if len(kinds) == 1 {
+		values = []string{id}
+	}
+
+	if len(values) != len(kinds) || hasEmptyValue(values) {
+		return nil, importIdFormatError(id, kinds)
+	}
+
+	return values, nil
+}
+
+func parseNamedImportId(id string, kinds []string) ([]string, error) {
+	values := make([]string, 0, len(kinds))
+
+	rest := strings.TrimPrefix(id, kinds[0]+":")
+	for _, kind := range kinds[1:] {
+		i := strings.Index(rest, "/"+kind+":")
+		if i < 0 {
+			return nil, importIdFormatError(id, kinds)
+		}
+
+		values = append(values, rest[:i])
+		rest = rest[i+len(kind)+2:]
+	}
+	values = append(values, rest)
+
+	if hasEmptyValue(values) {
+		return nil, importIdFormatError(id, kinds)
+	}
+
+	return values, nil
+}
+
+func hasEmptyValue(values []string) bool {
+	for _, value := range values {
+		if value == "" {
+			return true
+		}
+	}
+	return false
+}
+
+func importIdFormatError(id string, kinds []string) error {
+	named := make([]string, 0, len(kinds))
+	ids := make([]string, 0, len(kinds))
+	for _, kind := range kinds {
+		named = append(named, fmt.Sprintf("%s:${name}", kind))
+		ids = append(ids, fmt.Sprintf("${%sId}", kind))
+	}
+
+	return fmt.Errorf("invalid import ID %q: expected %s or %s", id, strings.Join(named, "/"), strings.Join(ids, "/"))
+}
+
+// findImportMatch returns the item with the ID or, failing that, the only item with the name
+func findImportMatch[T any](kind, value string, items []T, id func(T) string, names func(T) []string) (T, error) {
+	var empty T
+
+	for _, item := range items {
+		if id(item) == value {
+			return item, nil
+		}
+	}
+
+	matches := make([]T, 0)
+	for _, item := range items {
+		for _, name := range names(item) {
+			if name == value {
+				matches = append(matches, item)
+				break
+			}
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return empty, fmt.Errorf("could not find a %s with the ID or name %q", kind, value)
+	case 1:
+		return matches[0], nil
+	}
+
+	matchedIds := make([]string, 0, len(matches))
+	for _, match := range matches {
+		matchedIds = append(matchedIds, id(match))
+	}
+
+	return empty, fmt.Errorf("found %d %ss named %q (%s), import it by ID instead", len(matches), kind, value, strings.Join(matchedIds, ", "))
+}
+
+func resolveOrganization(apiClient pritunl.Client, value string) (pritunl.Organization, error) {
+	organizations, err := apiClient.GetOrganizations()
+	if err != nil {
+		return pritunl.Organization{}, err
+	}
+
+	return findImportMatch("organization", value, organizations,
+		func(organization pritunl.Organization) string { return organization.ID },
+		func(organization pritunl.Organization) []string { return []string{organization.Name} },
+	)
+}
+
+func resolveUser(apiClient pritunl.Client, organizationId, value string) (pritunl.User, error) {
+	users, err := apiClient.GetUsers(organizationId)
+	if err != nil {
+		return pritunl.User{}, err
+	}
+
+	return findImportMatch("user", value, users,
+		func(user pritunl.User) string { return user.ID },
+		func(user pritunl.User) []string { return []string{user.Name} },
+	)
+}
+
+func resolveServer(apiClient pritunl.Client, value string) (pritunl.Server, error) {
+	servers, err := apiClient.GetServers()
+	if err != nil {
+		return pritunl.Server{}, err
+	}
+
+	return findImportMatch("server", value, servers,
+		func(server pritunl.Server) string { return server.ID },
+		func(server pritunl.Server) []string { return []string{server.Name} },
+	)
+}
+
+// resolveRoute matches the route ID or the network, e.g. 10.0.0.0/16
+func resolveRoute(apiClient pritunl.Client, serverId, value string) (pritunl.Route, error) {
+	routes, err := apiClient.GetRoutesByServer(serverId)
+	if err != nil {
+		return pritunl.Route{}, err
+	}
+
+	for _, route := range routes {
+		if route.ID == value {
+			return route, nil
+		}
+	}
+
+	// networks are unique on a server
+	for _, route := range routes {
+		if !route.VirtualNetwork && sameNetwork(route.Network, value) {
+			return route, nil
+		}
+	}
+
+	return pritunl.Route{}, fmt.Errorf("could not find a route with the ID or network %q", value)
+}
+
+func resolveHost(apiClient pritunl.Client, value string) (pritunl.Host, error) {
+	hosts, err := apiClient.GetHosts()
+	if err != nil {
+		return pritunl.Host{}, err
+	}
+
+	return findImportMatch("host", value, hosts,
+		func(host pritunl.Host) string { return host.ID },
+		func(host pritunl.Host) []string { return []string{host.Name, host.Hostname} },
+	)
+}
+
+func resolveAdministrator(apiClient pritunl.Client, value string) (pritunl.Administrator, error) {
+	administrators, err := apiClient.GetAdministrators()
+	if err != nil {
+		return pritunl.Administrator{}, err
+	}
+
+	return findImportMatch("administrator", value, administrators,
+		func(administrator pritunl.Administrator) string { return administrator.ID },
+		func(administrator pritunl.Administrator) []string { return []string{administrator.Username} },
+	)
+}
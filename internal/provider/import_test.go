@@ -0,0 +1,136 @@
+package provider
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/maulid7/terraform-provider-pritunl/pritunl"
+)
+
+func TestParseImportId(t *testing.T) {
+	tests := []struct {
+		name   string
+		id     string
+		kinds  []string
+		values []string
+	}{
+		{"named", "org:Developers/user:alice", []string{"org", "user"}, []string{"Developers", "alice"}},
+		{"named network", "server:prod-vpn/route:10.0.0.0/16", []string{"server", "route"}, []string{"prod-vpn", "10.0.0.0/16"}},
+		{"named with dash", "server:prod-vpn/route:my-route", []string{"server", "route"}, []string{"prod-vpn", "my-route"}},
+		{"named single", "server:prod/vpn", []string{"server"}, []string{"prod/vpn"}},
+		{"ids", "5f1/6a2", []string{"org", "user"}, []string{"5f1", "6a2"}},
+		{"legacy ids", "5f1-6a2", []string{"org", "user"}, []string{"5f1", "6a2"}},
+		{"single id", "5f1", []string{"server"}, []string{"5f1"}},
+		{"single name with dash", "prod-vpn", []string{"server"}, []string{"prod-vpn"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			values, err := parseImportId(test.id, test.kinds...)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !reflect.DeepEqual(values, test.values) {
+				t.Errorf("expected %q, got %q", test.values, values)
+			}
+		})
+	}
+}
+
+func TestParseImportIdInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		id    string
+		kinds []string
+	}{
+		{"missing value", "5f1", []string{"org", "user"}},
+		{"empty value", "5f1/", []string{"org", "user"}},
+		{"missing kind", "org:Developers/alice", []string{"org", "user"}},
+		{"empty name", "org:/user:alice", []string{"org", "user"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := parseImportId(test.id, test.kinds...)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if !strings.Contains(err.Error(), "org:${name}/user:${name}") {
+				t.Errorf("expected the supported formats in the error, got %q", err)
+			}
+		})
+	}
+}
+
+// importClient returns fixed servers and routes
+type importClient struct {
+	pritunl.Client
+	servers []pritunl.Server
+	routes  []pritunl.Route
+}
+
+func (c importClient) GetServers() ([]pritunl.Server, error) {
+	return c.servers, nil
+}
+
+func (c importClient) GetRoutesByServer(serverId string) ([]pritunl.Route, error) {
+	return c.routes, nil
+}
+
+func TestResolveServer(t *testing.T) {
+	apiClient := importClient{
+		servers: []pritunl.Server{
+			{ID: "1", Name: "prod"},
+			{ID: "2", Name: "staging"},
+			{ID: "3", Name: "staging"},
+			{ID: "4", Name: "1"},
+		},
+	}
+
+	server, err := resolveServer(apiClient, "prod")
+	if err != nil || server.ID != "1" {
+		t.Errorf("expected the server 1 by name, got %q: %v", server.ID, err)
+	}
+
+	// IDs take precedence over names
+	server, err = resolveServer(apiClient, "1")
+	if err != nil || server.ID != "1" {
+		t.Errorf("expected the server 1 by ID, got %q: %v", server.ID, err)
+	}
+
+	_, err = resolveServer(apiClient, "staging")
+	if err == nil || !strings.Contains(err.Error(), "found 2 servers") {
+		t.Errorf("expected an ambiguous name error, got %v", err)
+	}
+
+	_, err = resolveServer(apiClient, "dev")
+	if err == nil {
+		t.Error("expected a not found error")
+	}
+}
+
+func TestResolveRoute(t *testing.T) {
+	apiClient := importClient{
+		routes: []pritunl.Route{
+			{ID: "a", Network: "10.0.0.0/16", VirtualNetwork: true},
+			{ID: "b", Network: "10.0.0.0/16"},
+			{ID: "c", Network: "8.8.8.8/32"},
+		},
+	}
+
+	route, err := resolveRoute(apiClient, "server", "10.0.0.0/16")
+	if err != nil || route.ID != "b" {
+		t.Errorf("expected the route b, got %q: %v", route.ID, err)
+	}
+
+	route, err = resolveRoute(apiClient, "server", "c")
+	if err != nil || route.ID != "c" {
+		t.Errorf("expected the route c, got %q: %v", route.ID, err)
+	}
+
+	_, err = resolveRoute(apiClient, "server", "1.1.1.1/32")
+	if err == nil {
+		t.Error("expected a not found error")
+	}
+}
@@ -0,0 +1,74 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/maulid7/terraform-provider-pritunl/pritunl"
+)
+
+// apiLogSubsystem is the tflog subsystem of the API requests, its level can be set with
+// TF_LOG_PROVIDER_PRITUNL_API
+const apiLogSubsystem = "pritunl_api"
+
+// apiLogger logs the messages of the Pritunl client in the pritunl_api subsystem of the
+// provider logger of the request context, see requestClient
+type apiLogger struct {
+	level hclog.Level
+}
+
+// newApiLogger returns the logger with the levels read from the environment variables with getenv
+func newApiLogger(getenv func(string) string) apiLogger {
+	return apiLogger{level: apiLogLevel(getenv)}
+}
+
+// apiLogLevel returns the lowest level of the messages Terraform keeps: the provider logs are
+// filtered with TF_LOG_PROVIDER or TF_LOG, and the subsystem with TF_LOG_PROVIDER_PRITUNL_API
+func apiLogLevel(getenv func(string) string) hclog.Level {
+	terraformLevel := envLogLevel(getenv, "TF_LOG_PROVIDER", "TF_LOG")
+	if terraformLevel == hclog.NoLevel {
+		return hclog.Off
+	}
+
+	subsystemLevel := envLogLevel(getenv, "TF_LOG_PROVIDER_PRITUNL_API")
+	if subsystemLevel > terraformLevel {
+		return subsystemLevel
+	}
+	return terraformLevel
+}
+
+// envLogLevel returns the level of the first variable set, Terraform logs everything for the
+// values that aren't a level, e.g. TF_LOG=JSON
+func envLogLevel(getenv func(string) string, names ...string) hclog.Level {
+	for _, name := range names {
+		value := getenv(name)
+		if value == "" {
+			continue
+		}
+
+		if level := hclog.LevelFromString(value); level != hclog.NoLevel {
+			return level
+		}
+		return hclog.Trace
+	}
+
+	return hclog.NoLevel
+}
+
+func (l apiLogger) Enabled(level pritunl.LogLevel) bool {
+	if level == pritunl.LogLevelTrace {
+		return l.level <= hclog.Trace
+	}
+	return l.level <= hclog.Debug
+}
+
+func (l apiLogger) Log(ctx context.Context, level pritunl.LogLevel, message string, fields map[string]interface{}) {
+	ctx = tflog.NewSubsystem(ctx, apiLogSubsystem, tflog.WithLevelFromEnv("TF_LOG_PROVIDER_PRITUNL_API"))
+
+	if level == pritunl.LogLevelTrace {
+		tflog.SubsystemTrace(ctx, apiLogSubsystem, message, fields)
+	} else {
+		tflog.SubsystemDebug(ctx, apiLogSubsystem, message, fields)
+	}
+}
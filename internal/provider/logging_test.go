@@ -0,0 +1,65 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+	"github.com/maulid7/terraform-provider-pritunl/pritunl"
+)
+
+func TestApiLogLevel(t *testing.T) {
+	tests := []struct {
+		name        string
+		environment map[string]string
+		expected    hclog.Level
+	}{
+		{"unset", map[string]string{}, hclog.Off},
+		{"subsystem only", map[string]string{"TF_LOG_PROVIDER_PRITUNL_API": "TRACE"}, hclog.Off},
+		{"terraform", map[string]string{"TF_LOG": "DEBUG"}, hclog.Debug},
+		{"json", map[string]string{"TF_LOG": "JSON"}, hclog.Trace},
+		{"provider", map[string]string{"TF_LOG": "INFO", "TF_LOG_PROVIDER": "TRACE"}, hclog.Trace},
+		{"quieter subsystem", map[string]string{"TF_LOG_PROVIDER": "TRACE", "TF_LOG_PROVIDER_PRITUNL_API": "DEBUG"}, hclog.Debug},
+		{"filtered subsystem", map[string]string{"TF_LOG": "DEBUG", "TF_LOG_PROVIDER_PRITUNL_API": "TRACE"}, hclog.Debug},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			getenv := func(name string) string { return test.environment[name] }
+			if level := apiLogLevel(getenv); level != test.expected {
+				t.Errorf("expected %s, got %s", test.expected, level)
+			}
+		})
+	}
+}
+
+func TestApiLogger(t *testing.T) {
+	var logs bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &logs)
+
+	logger := newApiLogger(func(name string) string {
+		if name == "TF_LOG" {
+			return "DEBUG"
+		}
+		return ""
+	})
+	if !logger.Enabled(pritunl.LogLevelDebug) || logger.Enabled(pritunl.LogLevelTrace) {
+		t.Errorf("expected the DEBUG level only, got %s", logger.level)
+	}
+
+	logger.Log(ctx, pritunl.LogLevelDebug, "Received HTTP response", map[string]interface{}{"status": 200})
+
+	entries, err := tflogtest.MultilineJSONDecode(&logs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected one entry, got %v", entries)
+	}
+	entry := entries[0]
+	if entry["@module"] != "provider."+apiLogSubsystem || entry["@level"] != "debug" || entry["status"] != float64(200) {
+		t.Errorf("unexpected entry %v", entry)
+	}
+}
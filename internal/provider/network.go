@@ -0,0 +1,139 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+)
+
+// parseNetwork returns the parsed CIDR or nil when the value is empty or invalid.
+func parseNetwork(cidr string) *net.IPNet {
+	if cidr == "" {
+		return nil
+	}
+
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil
+	}
+
+	return ipNet
+}
+
+// networksOverlap reports whether two CIDR networks share at least one address.
+func networksOverlap(a, b string) bool {
+	aNet := parseNetwork(a)
+	bNet := parseNetwork(b)
+	if aNet == nil || bNet == nil {
+		return false
+	}
+
+	return aNet.Contains(bNet.IP) || bNet.Contains(aNet.IP)
+}
+
+// sameNetwork reports whether two CIDR strings describe the same network, e.g. 10.0.0.1/24 and 10.0.0.0/24.
+func sameNetwork(a, b string) bool {
+	aNet := parseNetwork(a)
+	bNet := parseNetwork(b)
+	if aNet == nil || bNet == nil {
+		return a == b
+	}
+
+	return aNet.String() == bNet.String()
+}
+
+// compareIPs returns -1, 0 or 1 depending on the order of two IP addresses of the same family.
+func compareIPs(a, b net.IP) int {
+	if a4, b4 := a.To4(), b.To4(); a4 != nil && b4 != nil {
+		return bytes.Compare(a4, b4)
+	}
+
+	return bytes.Compare(a.To16(), b.To16())
+}
+
+type serverEndpoint struct {
+	protocol string
+	port     int
+}
+
+// serverEndpoints returns the protocol/port pairs the server listens on, including the WireGuard port.
+func serverEndpoints(protocol string, port int, wg bool, portWG int) []serverEndpoint {
+	endpoints := make([]serverEndpoint, 0)
+
+	if port > 0 {
+		endpoints = append(endpoints, serverEndpoint{protocol: protocol, port: port})
+	}
+
+	if wg && portWG > 0 {
+		// WireGuard always listens on UDP
+		endpoints = append(endpoints, serverEndpoint{protocol: "udp", port: portWG})
+	}
+
+	return endpoints
+}
+
+// allocateNetwork returns the first subnet of the pool with the given prefix length that overlaps none of the used networks.
+func allocateNetwork(pool string, prefixLength int, usedNetworks []string) (string, error) {
+	poolNet := parseNetwork(pool)
+	if poolNet == nil || poolNet.IP.To4() == nil {
+		return "", fmt.Errorf("pool %s must be a valid IPv4 CIDR", pool)
+	}
+
+	poolOnes, _ := poolNet.Mask.Size()
+	if prefixLength < poolOnes || prefixLength > 32 {
+		return "", fmt.Errorf("prefix length /%d must be between the pool prefix /%d and /32", prefixLength, poolOnes)
+	}
+
+	used := make([]*net.IPNet, 0)
+	for _, network := range usedNetworks {
+		if usedNet := parseNetwork(network); usedNet != nil && usedNet.IP.To4() != nil {
+			used = append(used, usedNet)
+		}
+	}
+
+	poolStart := uint64(binary.BigEndian.Uint32(poolNet.IP.To4()))
+	poolEnd := poolStart + 1<<(32-poolOnes)
+	size := uint64(1) << (32 - prefixLength)
+
+	for candidate := poolStart; candidate+size <= poolEnd; {
+		candidateIP := make(net.IP, net.IPv4len)
+		binary.BigEndian.PutUint32(candidateIP, uint32(candidate))
+		candidateNet := &net.IPNet{IP: candidateIP, Mask: net.CIDRMask(prefixLength, 32)}
+
+		overlapping := false
+		next := candidate + size
+		for _, usedNet := range used {
+			if !candidateNet.Contains(usedNet.IP) && !usedNet.Contains(candidateNet.IP) {
+				continue
+			}
+			overlapping = true
+
+			// skip every candidate covered by the used network
+			usedOnes, _ := usedNet.Mask.Size()
+			usedEnd := uint64(binary.BigEndian.Uint32(usedNet.IP.To4())) + 1<<(32-usedOnes)
+			if usedEnd > next {
+				next = (usedEnd + size - 1) / size * size
+			}
+		}
+
+		if !overlapping {
+			return candidateNet.String(), nil
+		}
+
+		candidate = next
+	}
+
+	return "", fmt.Errorf("no free /%d network left in the pool %s", prefixLength, pool)
+}
+
+// allocatePort returns the lowest port of the range that is not used.
+func allocatePort(portRangeStart, portRangeEnd int, usedPorts map[int]struct{}) (int, error) {
+	for port := portRangeStart; port <= portRangeEnd; port++ {
+		if _, ok := usedPorts[port]; !ok {
+			return port, nil
+		}
+	}
+
+	return 0, fmt.Errorf("no free port left in the range %d-%d", portRangeStart, portRangeEnd)
+}
@@ -0,0 +1,125 @@
+package provider
+
+import (
+	"net"
+	"testing"
+)
+
+func TestNetworksOverlap(t *testing.T) {
+	testCases := []struct {
+		a, b     string
+		expected bool
+	}{
+		{"10.0.0.0/24", "10.0.0.0/24", true},
+		{"10.0.0.0/16", "10.0.5.0/24", true},
+		{"10.0.5.0/24", "10.0.0.0/16", true},
+		{"10.0.0.0/24", "10.0.1.0/24", false},
+		{"192.168.0.0/16", "172.16.0.0/12", false},
+		{"10.0.0.0/24", "", false},
+		{"invalid", "10.0.0.0/24", false},
+	}
+
+	for _, tc := range testCases {
+		if actual := networksOverlap(tc.a, tc.b); actual != tc.expected {
+			t.Errorf("networksOverlap(%q, %q) = %v, expected %v", tc.a, tc.b, actual, tc.expected)
+		}
+	}
+}
+
+func TestSameNetwork(t *testing.T) {
+	testCases := []struct {
+		a, b     string
+		expected bool
+	}{
+		{"10.0.0.0/24", "10.0.0.0/24", true},
+		{"10.0.0.1/24", "10.0.0.0/24", true},
+		{"10.0.0.0/24", "10.0.0.0/25", false},
+		{"8.8.8.8/32", "8.8.4.4/32", false},
+	}
+
+	for _, tc := range testCases {
+		if actual := sameNetwork(tc.a, tc.b); actual != tc.expected {
+			t.Errorf("sameNetwork(%q, %q) = %v, expected %v", tc.a, tc.b, actual, tc.expected)
+		}
+	}
+}
+
+func TestCompareIPs(t *testing.T) {
+	if compareIPs(net.ParseIP("10.0.0.10"), net.ParseIP("10.0.0.9")) <= 0 {
+		t.Errorf("expected 10.0.0.10 to be greater than 10.0.0.9")
+	}
+
+	if compareIPs(net.ParseIP("10.0.0.1"), net.ParseIP("10.0.0.1")) != 0 {
+		t.Errorf("expected equal addresses to be equal")
+	}
+}
+
+func TestServerEndpoints(t *testing.T) {
+	endpoints := serverEndpoints("tcp", 1194, true, 51820)
+
+	expected := []serverEndpoint{{"tcp", 1194}, {"udp", 51820}}
+	if len(endpoints) != len(expected) {
+		t.Fatalf("expected %d endpoints, got %d", len(expected), len(endpoints))
+	}
+
+	for i := range expected {
+		if endpoints[i] != expected[i] {
+			t.Errorf("expected endpoint %+v, got %+v", expected[i], endpoints[i])
+		}
+	}
+
+	if endpoints := serverEndpoints("udp", 1194, false, 51820); len(endpoints) != 1 {
+		t.Errorf("expected the WireGuard port to be ignored when WireGuard is disabled, got %+v", endpoints)
+	}
+}
+
+func TestAllocateNetwork(t *testing.T) {
+	testCases := []struct {
+		name         string
+		pool         string
+		prefixLength int
+		used         []string
+		expected     string
+		expectError  bool
+	}{
+		{"empty pool", "10.10.0.0/16", 24, nil, "10.10.0.0/24", false},
+		{"skips used networks", "10.10.0.0/16", 24, []string{"10.10.0.0/24", "10.10.1.0/24"}, "10.10.2.0/24", false},
+		{"skips a larger used network", "10.10.0.0/16", 24, []string{"10.10.0.0/22"}, "10.10.4.0/24", false},
+		{"skips a smaller used network", "10.10.0.0/16", 24, []string{"10.10.0.128/25"}, "10.10.1.0/24", false},
+		{"ignores networks outside of the pool", "10.10.0.0/16", 24, []string{"192.168.0.0/24"}, "10.10.0.0/24", false},
+		{"exhausted pool", "10.10.0.0/23", 24, []string{"10.10.0.0/24", "10.10.1.0/24"}, "", true},
+		{"prefix shorter than the pool", "10.10.0.0/16", 8, nil, "", true},
+		{"IPv6 pool", "fd00::/64", 96, nil, "", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := allocateNetwork(tc.pool, tc.prefixLength, tc.used)
+			if tc.expectError {
+				if err == nil {
+					t.Fatalf("expected an error, got %s", actual)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if actual != tc.expected {
+				t.Errorf("expected %s, got %s", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestAllocatePort(t *testing.T) {
+	port, err := allocatePort(10000, 10002, map[int]struct{}{10000: {}})
+	if err != nil || port != 10001 {
+		t.Errorf("expected port 10001, got %d (%v)", port, err)
+	}
+
+	_, err = allocatePort(10000, 10001, map[int]struct{}{10000: {}, 10001: {}})
+	if err == nil {
+		t.Errorf("expected an error for an exhausted port range")
+	}
+}
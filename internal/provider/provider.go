@@ -2,10 +2,12 @@ package provider
 
 import (
 	"context"
+	"os"
 
-	"github.com/maulid7/terraform-provider-pritunl/internal/pritunl"
+	"github.com/maulid7/terraform-provider-pritunl/pritunl"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func Provider() *schema.Provider {
@@ -13,24 +15,94 @@ func Provider() *schema.Provider {
 		Schema: map[string]*schema.Schema{
 			"url": {
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("PRITUNL_URL", ""),
 			},
+			"urls": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "API URLs of the hosts of a Pritunl cluster, tried in order after the `url` when the connection fails, or when a read request gets a 502, 503 or 504 response",
+			},
+			"order_urls_by_health": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Check the API URLs when the provider is configured and try the failing ones last",
+			},
 			"token": {
 				Type:        schema.TypeString,
-				Required:    true,
-				DefaultFunc: schema.EnvDefaultFunc("PRITUNL_TOKEN", ""),
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"token_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Path of a file containing the API token, read again when the API responds 401 Unauthorized",
 			},
 			"secret": {
 				Type:        schema.TypeString,
-				Required:    true,
-				DefaultFunc: schema.EnvDefaultFunc("PRITUNL_SECRET", ""),
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"secret_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Path of a file containing the API secret, read again when the API responds 401 Unauthorized",
+			},
+			"credentials_command": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Command printing the API token and secret as JSON, e.g. `{\"token\": \"...\", \"secret\": \"...\"}`. It's run again when the API responds 401 Unauthorized and takes precedence over the credential environment variables.",
 			},
 			"insecure": {
 				Type:        schema.TypeBool,
 				Required:    true,
 				DefaultFunc: schema.EnvDefaultFunc("PRITUNL_INSECURE", false),
 			},
+			"ca_cert_pem": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"ca_cert_file"},
+				Description:   "PEM encoded CA certificates the Pritunl certificate is verified with instead of the system ones",
+			},
+			"ca_cert_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"ca_cert_pem"},
+				Description:   "Path of a file containing PEM encoded CA certificates, see `ca_cert_pem`",
+			},
+			"client_cert_pem": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				RequiredWith: []string{"client_key_pem"},
+				Description:  "PEM encoded client certificate presented to the server, e.g. to a reverse proxy enforcing mutual TLS",
+			},
+			"client_key_pem": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				RequiredWith: []string{"client_cert_pem"},
+				Description:  "PEM encoded private key of the `client_cert_pem`",
+			},
+			"tls_server_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Server name the Pritunl certificate is verified for and sent with SNI, the host of the `url` by default",
+			},
+			"min_tls_version": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"1.0", "1.1", "1.2", "1.3"}, false),
+				Description:  "Minimum TLS version, one of `1.0`, `1.1`, `1.2` or `1.3`",
+			},
+			"disable_body_logging": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("PRITUNL_DISABLE_BODY_LOGGING", false),
+				Description: "Keep the request and response bodies out of the TRACE logs, the secrets are redacted from them otherwise",
+			},
 			"connection_check": {
 				Type:        schema.TypeBool,
 				Optional:    true,
@@ -42,27 +114,75 @@ func Provider() *schema.Provider {
 			"pritunl_server":       resourceServer(),
 			"pritunl_user":         resourceUser(),
 			"pritunl_route":		resourceRoute(),
+			"pritunl_network_allocation": resourceNetworkAllocation(),
+			"pritunl_host":               resourceHost(),
+			"pritunl_settings":           resourceSettings(),
+			"pritunl_sso":                resourceSso(),
+			"pritunl_administrator":      resourceAdministrator(),
+			"pritunl_server_organization_attachment": resourceServerOrganizationAttachment(),
+			"pritunl_server_host_attachment":         resourceServerHostAttachment(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
-			"pritunl_host":  dataSourceHost(),
-			"pritunl_hosts": dataSourceHosts(),
+			"pritunl_host":             dataSourceHost(),
+			"pritunl_hosts":            dataSourceHosts(),
+			"pritunl_host_usage":       dataSourceHostUsage(),
+			"pritunl_server_bandwidth": dataSourceServerBandwidth(),
+			"pritunl_server_output":    dataSourceServerOutput(),
 		},
 		ConfigureContextFunc: providerConfigure,
 	}
 }
 
 func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
-	url := d.Get("url").(string)
-	token := d.Get("token").(string)
-	secret := d.Get("secret").(string)
+	urls, err := endpoints(d.Get("url").(string), expandStringList(d.Get("urls")))
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+
+	credentials := credentialsConfig{
+		token:              d.Get("token").(string),
+		secret:             d.Get("secret").(string),
+		tokenFile:          d.Get("token_file").(string),
+		secretFile:         d.Get("secret_file").(string),
+		credentialsCommand: d.Get("credentials_command").(string),
+	}.withEnvironment(os.Getenv)
 	insecure := d.Get("insecure").(bool)
 	connectionCheck := d.Get("connection_check").(bool)
 
-	apiClient := pritunl.NewClient(url, token, secret, insecure)
+	credentialsFunc, err := credentials.credentialsFunc()
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+
+	tlsConfig, err := tlsSettings{
+		caCertPem:     d.Get("ca_cert_pem").(string),
+		caCertFile:    d.Get("ca_cert_file").(string),
+		clientCertPem: d.Get("client_cert_pem").(string),
+		clientKeyPem:  d.Get("client_key_pem").(string),
+		serverName:    d.Get("tls_server_name").(string),
+		minVersion:    d.Get("min_tls_version").(string),
+	}.withEnvironment(os.Getenv).config()
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+
+	opts := []pritunl.Option{
+		pritunl.WithInsecure(insecure),
+		pritunl.WithTLSConfig(tlsConfig),
+		pritunl.WithUserAgent("terraform-provider-pritunl"),
+		pritunl.WithStructuredLogger(newApiLogger(os.Getenv)),
+		pritunl.WithBodyLogging(!d.Get("disable_body_logging").(bool)),
+	}
+
+	if d.Get("order_urls_by_health").(bool) && len(urls) > 1 {
+		urls = orderByHealth(ctx, urls, credentialsFunc, opts...)
+	}
+
+	apiClient := pritunl.NewClient(urls[0], append(opts, pritunl.WithCredentialsFunc(credentialsFunc), pritunl.WithFailoverUrls(urls[1:]...))...)
 
 	if connectionCheck {
 		// execute test api call to ensure that provided credentials are valid and pritunl api works
-		err := apiClient.TestApiCall()
+		err := apiClient.WithContext(ctx).TestApiCall()
 		if err != nil {
 			return nil, diag.FromErr(err)
 		}
@@ -70,3 +190,10 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 
 	return apiClient, nil
 }
+
+// requestClient returns the client of the provider sending its requests with the context of
+// the Terraform request, so they are logged with its fields. The requests aren't canceled with
+// the context, a server stopped for a change is still started again when the change fails.
+func requestClient(ctx context.Context, meta interface{}) pritunl.Client {
+	return meta.(pritunl.Client).WithContext(context.WithoutCancel(ctx))
+}
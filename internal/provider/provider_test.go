@@ -2,7 +2,7 @@ package provider
 
 import (
 	"fmt"
-	"github.com/maulid7/terraform-provider-pritunl/internal/pritunl"
+	"github.com/maulid7/terraform-provider-pritunl/pritunl"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
@@ -30,7 +30,7 @@ func TestMain(m *testing.M) {
 	secret := os.Getenv("PRITUNL_SECRET")
 	insecure, _ := strconv.ParseBool(os.Getenv("PRITUNL_INSECURE"))
 
-	testClient = pritunl.NewClient(url, token, secret, insecure)
+	testClient = pritunl.NewClient(url, pritunl.WithCredentials(token, secret), pritunl.WithInsecure(insecure))
 	err := testClient.TestApiCall()
 	if err != nil {
 		panic(err)
@@ -39,6 +39,12 @@ func TestMain(m *testing.M) {
 	resource.TestMain(m)
 }
 
+func TestProvider(t *testing.T) {
+	if err := Provider().InternalValidate(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
 func preCheck(t *testing.T) {
 	variables := []string{
 		"PRITUNL_URL",
@@ -68,6 +74,14 @@ func importStep(name string, ignore ...string) resource.TestStep {
 	return step
 }
 
+// namedImportStep imports the resource with a name-based ID, e.g. server:${name}
+func namedImportStep(name, importId string, ignore ...string) resource.TestStep {
+	step := importStep(name, ignore...)
+	step.ImportStateId = importId
+
+	return step
+}
+
 // pritunl_user import requires organization and user IDs
 func pritunlUserImportStep(name string) resource.TestStep {
 	step := resource.TestStep{
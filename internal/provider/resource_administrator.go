@@ -0,0 +1,204 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/maulid7/terraform-provider-pritunl/pritunl"
+)
+
+func resourceAdministrator() *schema.Resource {
+	return &schema.Resource{
+		Description: "The administrator resource allows managing Pritunl administrators and their API credentials.",
+		Schema: map[string]*schema.Schema{
+			"username": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Username of the administrator",
+			},
+			"password": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Sensitive:   true,
+				Description: "Password of the administrator. Pritunl doesn't return the password, so changes made outside of Terraform aren't detected",
+			},
+			"super_user": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Grant the administrator full access, otherwise the administrator can only manage users",
+			},
+			"auth_api": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Allow the administrator to use the API with the token and secret",
+			},
+			"yubikey_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "ID of the YubiKey the administrator has to sign in with",
+			},
+			"otp_auth": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Require a two-step authentication code on sign in",
+			},
+			"disabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Prevent the administrator from signing in and using the API",
+			},
+			"rotation_trigger": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Arbitrary value that, when changed, regenerates the API token and secret",
+			},
+			"token": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "API token of the administrator",
+			},
+			"secret": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "API secret of the administrator",
+			},
+			"otp_secret": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Secret to set up the two-step authentication",
+			},
+		},
+		CreateContext: resourceCreateAdministrator,
+		ReadContext:   resourceReadAdministrator,
+		UpdateContext: resourceUpdateAdministrator,
+		DeleteContext: resourceDeleteAdministrator,
+		CustomizeDiff: resourceAdministratorCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceAdministratorImport,
+		},
+	}
+}
+
+// Imports by admin:${username} or the ID
+func resourceAdministratorImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	apiClient := requestClient(ctx, meta)
+
+	values, err := parseImportId(d.Id(), "admin")
+	if err != nil {
+		return nil, err
+	}
+
+	administrator, err := resolveAdministrator(apiClient, values[0])
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(administrator.ID)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// New credentials are generated on update, so they are unknown in the plan
+func resourceAdministratorCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" && d.HasChange("rotation_trigger") {
+		if err := d.SetNewComputed("token"); err != nil {
+			return err
+		}
+		return d.SetNewComputed("secret")
+	}
+
+	return nil
+}
+
+func resourceReadAdministrator(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	apiClient := requestClient(ctx, meta)
+
+	administrator, err := apiClient.GetAdministrator(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("username", administrator.Username)
+	d.Set("super_user", administrator.SuperUser)
+	d.Set("auth_api", administrator.AuthApi)
+	d.Set("yubikey_id", administrator.YubikeyId)
+	d.Set("otp_auth", administrator.OtpAuth)
+	d.Set("disabled", administrator.Disabled)
+	d.Set("token", administrator.Token)
+	d.Set("secret", administrator.Secret)
+	d.Set("otp_secret", administrator.OtpSecret)
+
+	return nil
+}
+
+func resourceCreateAdministrator(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	apiClient := requestClient(ctx, meta)
+
+	administrator, err := apiClient.CreateAdministrator(&pritunl.Administrator{
+		Username:  d.Get("username").(string),
+		Password:  pritunl.WriteOnlyString(d.Get("password").(string)),
+		SuperUser: d.Get("super_user").(bool),
+		AuthApi:   d.Get("auth_api").(bool),
+		YubikeyId: d.Get("yubikey_id").(string),
+		OtpAuth:   d.Get("otp_auth").(bool),
+		Disabled:  d.Get("disabled").(bool),
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(administrator.ID)
+
+	return resourceReadAdministrator(ctx, d, meta)
+}
+
+func resourceUpdateAdministrator(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	apiClient := requestClient(ctx, meta)
+
+	administrator, err := apiClient.GetAdministrator(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	administrator.Username = d.Get("username").(string)
+	administrator.SuperUser = d.Get("super_user").(bool)
+	administrator.AuthApi = d.Get("auth_api").(bool)
+	administrator.YubikeyId = d.Get("yubikey_id").(string)
+	administrator.OtpAuth = d.Get("otp_auth").(bool)
+	administrator.Disabled = d.Get("disabled").(bool)
+
+	// Pritunl doesn't return the password, so it is sent only when it changes
+	if d.HasChange("password") {
+		administrator.Password = pritunl.WriteOnlyString(d.Get("password").(string))
+	}
+
+	administrator.RegenerateApiCredentials = d.HasChange("rotation_trigger")
+
+	_, err = apiClient.UpdateAdministrator(d.Id(), administrator)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceReadAdministrator(ctx, d, meta)
+}
+
+func resourceDeleteAdministrator(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	apiClient := requestClient(ctx, meta)
+
+	err := apiClient.DeleteAdministrator(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
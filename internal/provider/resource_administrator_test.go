@@ -0,0 +1,77 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccPritunlAdministrator(t *testing.T) {
+
+	t.Run("creates an administrator and rotates its API credentials", func(t *testing.T) {
+		username := "tfacc-admin1"
+		var token string
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:          func() { preCheck(t) },
+			ProviderFactories: providerFactories,
+			CheckDestroy:      testPritunlAdministratorDestroy,
+			Steps: []resource.TestStep{
+				{
+					Config: testPritunlAdministratorConfig(username, "1"),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr("pritunl_administrator.test", "username", username),
+						resource.TestCheckResourceAttr("pritunl_administrator.test", "auth_api", "true"),
+						resource.TestCheckResourceAttrSet("pritunl_administrator.test", "token"),
+						resource.TestCheckResourceAttrSet("pritunl_administrator.test", "secret"),
+						func(s *terraform.State) error {
+							token = s.RootModule().Resources["pritunl_administrator.test"].Primary.Attributes["token"]
+							return nil
+						},
+					),
+				},
+				{
+					Config: testPritunlAdministratorConfig(username, "2"),
+					Check: resource.ComposeTestCheckFunc(
+						func(s *terraform.State) error {
+							if s.RootModule().Resources["pritunl_administrator.test"].Primary.Attributes["token"] == token {
+								return fmt.Errorf("the API token was not regenerated")
+							}
+							return nil
+						},
+					),
+				},
+				// import test
+				importStep("pritunl_administrator.test", "password", "rotation_trigger"),
+			},
+		})
+	})
+}
+
+func testPritunlAdministratorDestroy(s *terraform.State) error {
+	administratorId := s.RootModule().Resources["pritunl_administrator.test"].Primary.Attributes["id"]
+
+	administrators, err := testClient.GetAdministrators()
+	if err != nil {
+		return err
+	}
+	for _, administrator := range administrators {
+		if administrator.ID == administratorId {
+			return fmt.Errorf("an administrator is not destroyed")
+		}
+	}
+	return nil
+}
+
+func testPritunlAdministratorConfig(username, rotationTrigger string) string {
+	return fmt.Sprintf(`
+		resource "pritunl_administrator" "test" {
+			username         = "%[1]s"
+			password         = "tfacc-password"
+			auth_api         = true
+			rotation_trigger = "%[2]s"
+		}
+	`, username, rotationTrigger)
+}
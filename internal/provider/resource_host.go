@@ -0,0 +1,261 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/maulid7/terraform-provider-pritunl/pritunl"
+)
+
+func resourceHost() *schema.Resource {
+	return &schema.Resource{
+		Description: "The host resource allows managing settings of an existing Pritunl host. Hosts register themselves in Pritunl, so the resource adopts a host instead of creating it.",
+		Schema: map[string]*schema.Schema{
+			"host_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				Description:  "ID of the host to manage",
+				ExactlyOneOf: []string{"host_id", "hostname"},
+			},
+			"hostname": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				Description:  "Hostname of the host to manage",
+				ExactlyOneOf: []string{"host_id", "hostname"},
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Name of host",
+			},
+			"public_addr": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Public IP address or domain name of the host",
+			},
+			"public_addr6": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Public IPv6 address or domain name of the host",
+			},
+			"routed_subnet6": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Description:  "IPv6 subnet that is routed to the host",
+				ValidateFunc: validation.Any(validation.StringIsEmpty, validation.IsCIDR),
+			},
+			"routed_subnet6_wg": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Description:  "IPv6 WG subnet that is routed to the host",
+				ValidateFunc: validation.Any(validation.StringIsEmpty, validation.IsCIDR),
+			},
+			"local_addr": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Local network address for server",
+			},
+			"local_addr6": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Local IPv6 network address for server",
+			},
+			"link_addr": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "IP address or domain used when linked servers connect to a linked server on this host",
+			},
+			"sync_address": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "IP address or domain used by users when syncing configuration. This is needed when using a load balancer.",
+			},
+			"availability_group": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Availability group for host. Replicated servers will only be replicated to a group of hosts in the same availability group",
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Status of host",
+			},
+			"remove_on_destroy": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Remove the host from Pritunl on destroy. By default the host is only released from Terraform management",
+			},
+		},
+		CreateContext: resourceCreateHost,
+		ReadContext:   resourceReadHost,
+		UpdateContext: resourceUpdateHost,
+		DeleteContext: resourceDeleteHost,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceHostImport,
+		},
+	}
+}
+
+// Host attributes managed by the resource, the key matches the JSON attribute name
+var hostAttributes = []string{
+	"name", "public_addr", "public_addr6", "routed_subnet6", "routed_subnet6_wg",
+	"local_addr", "local_addr6", "link_addr", "sync_address", "availability_group",
+}
+
+// Imports by host:${name}, host:${hostname} or the ID
+func resourceHostImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	apiClient := requestClient(ctx, meta)
+
+	values, err := parseImportId(d.Id(), "host")
+	if err != nil {
+		return nil, err
+	}
+
+	host, err := resolveHost(apiClient, values[0])
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(host.ID)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceReadHost(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	apiClient := requestClient(ctx, meta)
+
+	host, err := apiClient.GetHost(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("host_id", host.ID)
+	d.Set("hostname", host.Hostname)
+	d.Set("name", host.Name)
+	d.Set("public_addr", host.PublicAddr)
+	d.Set("public_addr6", host.PublicAddr6)
+	d.Set("routed_subnet6", host.RoutedSubnet6)
+	d.Set("routed_subnet6_wg", host.RoutedSubnet6WG)
+	d.Set("local_addr", host.LocalAddr)
+	d.Set("local_addr6", host.LocalAddr6)
+	d.Set("link_addr", host.LinkAddr)
+	d.Set("sync_address", host.SyncAddress)
+	d.Set("availability_group", host.AvailabilityGroup)
+	d.Set("status", host.Status)
+
+	return nil
+}
+
+// Adopts an existing host, Pritunl hosts cannot be created through the API
+func resourceCreateHost(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	hostId := d.Get("host_id").(string)
+	hostname := d.Get("hostname").(string)
+
+	host, err := filterHosts(requestClient(ctx, meta), func(host pritunl.Host) bool {
+		if hostId != "" {
+			return host.ID == hostId
+		}
+		return host.Hostname == hostname
+	})
+	if err != nil {
+		return diag.Errorf("could not find host to adopt (host_id=%q, hostname=%q). Previous error message: %v", hostId, hostname, err)
+	}
+
+	d.SetId(host.ID)
+
+	for _, key := range hostAttributes {
+		if _, ok := d.GetOk(key); ok {
+			return resourceUpdateHost(ctx, d, meta)
+		}
+	}
+
+	return resourceReadHost(ctx, d, meta)
+}
+
+func resourceUpdateHost(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	apiClient := requestClient(ctx, meta)
+
+	host, err := apiClient.GetHost(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.HasChange("name") {
+		host.Name = d.Get("name").(string)
+	}
+
+	if d.HasChange("public_addr") {
+		host.PublicAddr = d.Get("public_addr").(string)
+	}
+
+	if d.HasChange("public_addr6") {
+		host.PublicAddr6 = d.Get("public_addr6").(string)
+	}
+
+	if d.HasChange("routed_subnet6") {
+		host.RoutedSubnet6 = d.Get("routed_subnet6").(string)
+	}
+
+	if d.HasChange("routed_subnet6_wg") {
+		host.RoutedSubnet6WG = d.Get("routed_subnet6_wg").(string)
+	}
+
+	if d.HasChange("local_addr") {
+		host.LocalAddr = d.Get("local_addr").(string)
+	}
+
+	if d.HasChange("local_addr6") {
+		host.LocalAddr6 = d.Get("local_addr6").(string)
+	}
+
+	if d.HasChange("link_addr") {
+		host.LinkAddr = d.Get("link_addr").(string)
+	}
+
+	if d.HasChange("sync_address") {
+		host.SyncAddress = d.Get("sync_address").(string)
+	}
+
+	if d.HasChange("availability_group") {
+		host.AvailabilityGroup = d.Get("availability_group").(string)
+	}
+
+	err = apiClient.UpdateHost(d.Id(), host)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceReadHost(ctx, d, meta)
+}
+
+func resourceDeleteHost(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	apiClient := requestClient(ctx, meta)
+
+	if d.Get("remove_on_destroy").(bool) {
+		err := apiClient.DeleteHost(d.Id())
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	d.SetId("")
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccPritunlHost(t *testing.T) {
+
+	t.Run("adopts an existing host and updates its settings", func(t *testing.T) {
+		// pritunl.local sets in Makefile's "test" target
+		hostname := "pritunl.local"
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:          func() { preCheck(t) },
+			ProviderFactories: providerFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: testPritunlHostConfig(hostname, "tfacc-group1", "vpn1.example.com"),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr("pritunl_host.test", "hostname", hostname),
+						resource.TestCheckResourceAttr("pritunl_host.test", "availability_group", "tfacc-group1"),
+						resource.TestCheckResourceAttr("pritunl_host.test", "sync_address", "vpn1.example.com"),
+						resource.TestCheckResourceAttrSet("pritunl_host.test", "host_id"),
+					),
+				},
+				{
+					Config: testPritunlHostConfig(hostname, "default", "vpn2.example.com"),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr("pritunl_host.test", "availability_group", "default"),
+						resource.TestCheckResourceAttr("pritunl_host.test", "sync_address", "vpn2.example.com"),
+					),
+				},
+				// import test
+				importStep("pritunl_host.test", "remove_on_destroy"),
+			},
+		})
+	})
+}
+
+func testPritunlHostConfig(hostname, availabilityGroup, syncAddress string) string {
+	return fmt.Sprintf(`
+		resource "pritunl_host" "test" {
+			hostname           = "%[1]s"
+			availability_group = "%[2]s"
+			sync_address       = "%[3]s"
+		}
+	`, hostname, availabilityGroup, syncAddress)
+}
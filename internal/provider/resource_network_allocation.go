@@ -0,0 +1,160 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// Networks and ports reserved during the current provider run, servers using them may not exist yet.
+// Plan and apply run in separate provider processes, so the allocations in the state are unknown here.
+var (
+	allocationMutex    sync.Mutex
+	allocatedNetworks  = make(map[string]struct{})
+	allocatedEndpoints = make(map[serverEndpoint]struct{})
+)
+
+func resourceNetworkAllocation() *schema.Resource {
+	return &schema.Resource{
+		Description: "The network allocation resource reserves a free network and port for a new Pritunl server. The networks and ports of the existing servers are avoided, but an allocation is only known to other allocations created in the same provider run: one that isn't used by a server yet can be handed out again by a later run, so create the servers in the same apply as their allocations.",
+		Schema: map[string]*schema.Schema{
+			"pool": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Parent IPv4 network CIDR to allocate the server network from",
+				ValidateFunc: validation.IsCIDR,
+			},
+			"prefix_length": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Prefix length of the allocated network",
+				ValidateFunc: validation.IntBetween(8, 30),
+			},
+			"protocol": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "udp",
+				Description:  "The protocol the allocated port will be used with",
+				ValidateFunc: validation.StringInSlice([]string{"udp", "tcp"}, false),
+			},
+			"port_range_start": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "First port of the range to allocate the server port from",
+				ValidateFunc: validation.IntBetween(1, 65535),
+			},
+			"port_range_end": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "Last port of the range to allocate the server port from",
+				ValidateFunc: validation.IntBetween(1, 65535),
+			},
+			"keepers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Arbitrary map of values that, when changed, will trigger a new allocation",
+			},
+			"network": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The allocated network",
+			},
+			"port": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The allocated port",
+			},
+		},
+		CreateContext: resourceCreateNetworkAllocation,
+		ReadContext:   resourceReadNetworkAllocation,
+		DeleteContext: resourceDeleteNetworkAllocation,
+	}
+}
+
+func resourceCreateNetworkAllocation(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	apiClient := requestClient(ctx, meta)
+
+	protocol := d.Get("protocol").(string)
+	portRangeStart := d.Get("port_range_start").(int)
+	portRangeEnd := d.Get("port_range_end").(int)
+	if portRangeStart > portRangeEnd {
+		return diag.Errorf("port_range_start %d must not be greater than port_range_end %d", portRangeStart, portRangeEnd)
+	}
+
+	allocationMutex.Lock()
+	defer allocationMutex.Unlock()
+
+	servers, err := apiClient.GetServers()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	usedNetworks := make([]string, 0)
+	for network := range allocatedNetworks {
+		usedNetworks = append(usedNetworks, network)
+	}
+
+	usedPorts := make(map[int]struct{})
+	for endpoint := range allocatedEndpoints {
+		if endpoint.protocol == protocol {
+			usedPorts[endpoint.port] = struct{}{}
+		}
+	}
+
+	for _, server := range servers {
+		usedNetworks = append(usedNetworks, server.Network, server.NetworkWG)
+
+		for _, endpoint := range serverEndpoints(server.Protocol, server.Port, server.WG, server.PortWG) {
+			if endpoint.protocol == protocol {
+				usedPorts[endpoint.port] = struct{}{}
+			}
+		}
+	}
+
+	network, err := allocateNetwork(d.Get("pool").(string), d.Get("prefix_length").(int), usedNetworks)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	port, err := allocatePort(portRangeStart, portRangeEnd, usedPorts)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	allocatedNetworks[network] = struct{}{}
+	allocatedEndpoints[serverEndpoint{protocol: protocol, port: port}] = struct{}{}
+
+	d.SetId(fmt.Sprintf("%s-%d", network, port))
+	d.Set("network", network)
+	d.Set("port", port)
+
+	return nil
+}
+
+// The allocation lives only in the state, so it stays stable even when the server using it is created
+func resourceReadNetworkAllocation(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return nil
+}
+
+func resourceDeleteNetworkAllocation(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	allocationMutex.Lock()
+	defer allocationMutex.Unlock()
+
+	delete(allocatedNetworks, d.Get("network").(string))
+	delete(allocatedEndpoints, serverEndpoint{protocol: d.Get("protocol").(string), port: d.Get("port").(int)})
+
+	d.SetId("")
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccPritunlNetworkAllocation(t *testing.T) {
+
+	t.Run("allocates a network and a port for a server", func(t *testing.T) {
+		serverName := "tfacc-server1"
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:          func() { preCheck(t) },
+			ProviderFactories: providerFactories,
+			CheckDestroy:      testPritunlServerDestroy,
+			Steps: []resource.TestStep{
+				{
+					Config: testPritunlNetworkAllocationConfig(serverName),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttrSet("pritunl_network_allocation.test", "network"),
+						resource.TestCheckResourceAttrSet("pritunl_network_allocation.test", "port"),
+						resource.TestCheckResourceAttrPair("pritunl_server.test", "network", "pritunl_network_allocation.test", "network"),
+						resource.TestCheckResourceAttrPair("pritunl_server.test", "port", "pritunl_network_allocation.test", "port"),
+					),
+				},
+				{
+					// the allocation must stay stable once the server uses it
+					Config:   testPritunlNetworkAllocationConfig(serverName),
+					PlanOnly: true,
+				},
+			},
+		})
+	})
+}
+
+func testPritunlNetworkAllocationConfig(name string) string {
+	return fmt.Sprintf(`
+		resource "pritunl_network_allocation" "test" {
+			pool             = "172.20.0.0/16"
+			prefix_length    = 24
+			port_range_start = 15000
+			port_range_end   = 15100
+		}
+
+		resource "pritunl_server" "test" {
+			name    = "%[1]s"
+			network = pritunl_network_allocation.test.network
+			port    = pritunl_network_allocation.test.port
+		}
+	`, name)
+}
@@ -2,7 +2,10 @@ package provider
 
 import (
 	"context"
-	"github.com/maulid7/terraform-provider-pritunl/internal/pritunl"
+	"fmt"
+	"strings"
+
+	"github.com/maulid7/terraform-provider-pritunl/pritunl"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -16,20 +19,87 @@ func resourceOrganization() *schema.Resource {
 				Required:    true,
 				Description: "The name of the resource, also acts as it's unique ID",
 			},
+			"auth_api": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Allow the user self-service API to authenticate with the auth token and secret of the organization",
+			},
+			"rotation_trigger": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Arbitrary value that, when changed, regenerates the auth token and secret",
+			},
+			"auth_token": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Auth token of the organization",
+			},
+			"auth_secret": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Auth secret of the organization",
+			},
+			"force_destroy": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Delete the organization even when it has users or is attached to servers. The users are deleted and the organization is detached from the servers, restarting the online ones",
+			},
+			"user_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of users in the organization",
+			},
 		},
 		CreateContext: resourceCreateOrganization,
 		ReadContext:   resourceReadOrganization,
 		UpdateContext: resourceUpdateOrganization,
 		DeleteContext: resourceDeleteOrganization,
+		CustomizeDiff: resourceOrganizationCustomizeDiff,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: resourceOrganizationImport,
 		},
 	}
 }
 
+// Imports by org:${name} or the ID
+func resourceOrganizationImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	apiClient := requestClient(ctx, meta)
+
+	values, err := parseImportId(d.Id(), "org")
+	if err != nil {
+		return nil, err
+	}
+
+	organization, err := resolveOrganization(apiClient, values[0])
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(organization.ID)
+	d.Set("force_destroy", false)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// New credentials are generated on update, so they are unknown in the plan
+func resourceOrganizationCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" && d.HasChange("rotation_trigger") {
+		if err := d.SetNewComputed("auth_token"); err != nil {
+			return err
+		}
+		return d.SetNewComputed("auth_secret")
+	}
+
+	return nil
+}
+
 // Uses for importing
 func resourceReadOrganization(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	apiClient := meta.(pritunl.Client)
+	apiClient := requestClient(ctx, meta)
 
 	organization, err := apiClient.GetOrganization(d.Id())
 	if err != nil {
@@ -37,33 +107,158 @@ func resourceReadOrganization(ctx context.Context, d *schema.ResourceData, meta
 	}
 
 	d.Set("name", organization.Name)
+	d.Set("auth_api", organization.AuthApi)
+	d.Set("auth_token", organization.AuthToken)
+	d.Set("auth_secret", organization.AuthSecret)
+	d.Set("user_count", organization.UserCount)
 
 	return nil
 }
 
 func resourceDeleteOrganization(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	apiClient := meta.(pritunl.Client)
+	apiClient := requestClient(ctx, meta)
+
+	organization, err := apiClient.GetOrganization(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	resourceMutex.Lock()
+	defer resourceMutex.Unlock()
 
-	err := apiClient.DeleteOrganization(d.Id())
+	servers, err := getServersByOrganization(apiClient, d.Id())
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
+	if !d.Get("force_destroy").(bool) && (organization.UserCount > 0 || len(servers) > 0) {
+		return diag.Diagnostics{
+			{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("The organization %s is in use", organization.Name),
+				Detail:   organizationInUseDetail(organization, servers),
+			},
+		}
+	}
+
+	var diags diag.Diagnostics
+	for _, server := range servers {
+		diags = append(diags, detachOrganizationFromServer(apiClient, d.Id(), server)...)
+		if diags.HasError() {
+			return diags
+		}
+	}
+
+	err = apiClient.DeleteOrganization(d.Id())
+	if err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+
 	d.SetId("")
 
-	return nil
+	return diags
+}
+
+func organizationInUseDetail(organization *pritunl.Organization, servers []pritunl.Server) string {
+	var detail strings.Builder
+
+	fmt.Fprintf(&detail, "Users: %d\n", organization.UserCount)
+
+	serverNames := make([]string, 0, len(servers))
+	for _, server := range servers {
+		serverNames = append(serverNames, fmt.Sprintf("%s (%s)", server.Name, server.Status))
+	}
+	if len(serverNames) == 0 {
+		serverNames = append(serverNames, "none")
+	}
+	fmt.Fprintf(&detail, "Attached servers: %s\n\n", strings.Join(serverNames, ", "))
+
+	detail.WriteString("Deleting the organization deletes its users and detaches it from the servers. Set force_destroy = true to delete it anyway.")
+
+	return detail.String()
+}
+
+// getServersByOrganization returns the servers the organization is attached to
+func getServersByOrganization(apiClient pritunl.Client, organizationId string) ([]pritunl.Server, error) {
+	servers, err := apiClient.GetServers()
+	if err != nil {
+		return nil, err
+	}
+
+	attachedServers := make([]pritunl.Server, 0)
+	for _, server := range servers {
+		organizations, err := apiClient.GetOrganizationsByServer(server.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, organization := range organizations {
+			if organization.ID == organizationId {
+				attachedServers = append(attachedServers, server)
+				break
+			}
+		}
+	}
+
+	return attachedServers, nil
+}
+
+// detachOrganizationFromServer detaches the organization, an online server is stopped for it and started again
+// unless no organization is left, Pritunl doesn't start servers without organizations
+func detachOrganizationFromServer(apiClient pritunl.Client, organizationId string, server pritunl.Server) diag.Diagnostics {
+	online := server.Status == pritunl.ServerStatusOnline
+
+	if online {
+		err := apiClient.StopServer(server.ID)
+		if err != nil {
+			return diag.Errorf("Error on stopping server %s: %s", server.Name, err)
+		}
+	}
+
+	err := apiClient.DetachOrganizationFromServer(organizationId, server.ID)
+	if err != nil {
+		// the server should still be running as before
+		diags := diag.Errorf("Error on detaching the organization from server %s: %s", server.Name, err)
+		if online {
+			diags = append(diags, startServer(apiClient, server.ID)...)
+		}
+		return diags
+	}
+
+	if !online {
+		return nil
+	}
+
+	organizations, err := apiClient.GetOrganizationsByServer(server.ID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if len(organizations) == 0 {
+		return diag.Diagnostics{
+			{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("Server %s was left offline", server.Name),
+				Detail:   "The server has no organizations attached after detaching the deleted organization, so it can't be started.",
+			},
+		}
+	}
+
+	return startServer(apiClient, server.ID)
 }
 
 func resourceUpdateOrganization(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	apiClient := meta.(pritunl.Client)
+	apiClient := requestClient(ctx, meta)
 
 	organization, err := apiClient.GetOrganization(d.Id())
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	if d.HasChange("name") {
+	if d.HasChanges("name", "auth_api", "rotation_trigger") {
 		organization.Name = d.Get("name").(string)
+		organization.AuthApi = d.Get("auth_api").(bool)
+		organization.RegenerateAuthCredentials = d.HasChange("rotation_trigger")
 
 		err = apiClient.UpdateOrganization(d.Id(), organization)
 		if err != nil {
@@ -71,11 +266,11 @@ func resourceUpdateOrganization(ctx context.Context, d *schema.ResourceData, met
 		}
 	}
 
-	return nil
+	return resourceReadOrganization(ctx, d, meta)
 }
 
 func resourceCreateOrganization(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	apiClient := meta.(pritunl.Client)
+	apiClient := requestClient(ctx, meta)
 
 	organization, err := apiClient.CreateOrganization(d.Get("name").(string))
 	if err != nil {
@@ -84,5 +279,15 @@ func resourceCreateOrganization(ctx context.Context, d *schema.ResourceData, met
 
 	d.SetId(organization.ID)
 
-	return nil
+	// the organization is created with the defaults, other attributes are set by an update
+	if d.Get("auth_api").(bool) {
+		organization.AuthApi = true
+
+		err = apiClient.UpdateOrganization(d.Id(), organization)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceReadOrganization(ctx, d, meta)
 }
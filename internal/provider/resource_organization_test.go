@@ -1,8 +1,13 @@
 package provider
 
 import (
+	"errors"
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/maulid7/terraform-provider-pritunl/pritunl"
+	"reflect"
+	"regexp"
 	"testing"
 )
 
@@ -25,11 +30,115 @@ func TestAccPritunlOrganization(t *testing.T) {
 				},
 				// import test
 				importStep("pritunl_organization.test"),
+				namedImportStep("pritunl_organization.test", "org:"+orgName),
+			},
+		})
+	})
+
+	t.Run("manages the auth API credentials", func(t *testing.T) {
+		orgName := "tfacc-org2"
+		var authToken string
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:          func() { preCheck(t) },
+			ProviderFactories: providerFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: testPritunlOrganizationConfigWithAuthApi(orgName, "1"),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr("pritunl_organization.test", "auth_api", "true"),
+						resource.TestCheckResourceAttr("pritunl_organization.test", "user_count", "0"),
+						resource.TestCheckResourceAttrSet("pritunl_organization.test", "auth_token"),
+						resource.TestCheckResourceAttrSet("pritunl_organization.test", "auth_secret"),
+						func(s *terraform.State) error {
+							authToken = s.RootModule().Resources["pritunl_organization.test"].Primary.Attributes["auth_token"]
+							return nil
+						},
+					),
+				},
+				{
+					Config: testPritunlOrganizationConfigWithAuthApi(orgName, "2"),
+					Check: resource.ComposeTestCheckFunc(
+						func(s *terraform.State) error {
+							if s.RootModule().Resources["pritunl_organization.test"].Primary.Attributes["auth_token"] == authToken {
+								return fmt.Errorf("the auth token was not regenerated")
+							}
+							return nil
+						},
+					),
+				},
+				// import test
+				importStep("pritunl_organization.test", "rotation_trigger"),
+			},
+		})
+	})
+
+	t.Run("deletes an organization with users only with force_destroy", func(t *testing.T) {
+		orgName := "tfacc-org3"
+		var orgId string
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:          func() { preCheck(t) },
+			ProviderFactories: providerFactories,
+			CheckDestroy: func(s *terraform.State) error {
+				organizations, err := testClient.GetOrganizations()
+				if err != nil {
+					return err
+				}
+				for _, organization := range organizations {
+					if organization.ID == orgId {
+						return fmt.Errorf("an organization is not destroyed")
+					}
+				}
+				return nil
+			},
+			Steps: []resource.TestStep{
+				{
+					Config: testPritunlOrganizationConfigWithForceDestroy(orgName, false),
+					Check: func(s *terraform.State) error {
+						orgId = s.RootModule().Resources["pritunl_organization.test"].Primary.ID
+						return nil
+					},
+				},
+				{
+					PreConfig: func() {
+						_, err := testClient.CreateUser(pritunl.User{Name: "tfacc-org3-user", Organization: orgId})
+						if err != nil {
+							t.Fatal(err)
+						}
+					},
+					Config:      testPritunlOrganizationConfigWithForceDestroy(orgName, false),
+					Destroy:     true,
+					ExpectError: regexp.MustCompile("The organization tfacc-org3 is in use"),
+				},
+				{
+					Config: testPritunlOrganizationConfigWithForceDestroy(orgName, true),
+					Check:  resource.TestCheckResourceAttr("pritunl_organization.test", "force_destroy", "true"),
+				},
 			},
 		})
 	})
 }
 
+func testPritunlOrganizationConfigWithForceDestroy(name string, forceDestroy bool) string {
+	return fmt.Sprintf(`
+		resource "pritunl_organization" "test" {
+			name          = "%[1]s"
+			force_destroy = %[2]v
+		}
+	`, name, forceDestroy)
+}
+
+func testPritunlOrganizationConfigWithAuthApi(name, rotationTrigger string) string {
+	return fmt.Sprintf(`
+		resource "pritunl_organization" "test" {
+			name             = "%[1]s"
+			auth_api         = true
+			rotation_trigger = "%[2]s"
+		}
+	`, name, rotationTrigger)
+}
+
 func testPritunlOrganizationConfig(name string) string {
 	return fmt.Sprintf(`
 		resource "pritunl_organization" "test" {
@@ -37,3 +146,36 @@ func testPritunlOrganizationConfig(name string) string {
 		}
 	`, name)
 }
+
+// detachFailingClient fails to detach organizations and records the server starts and stops
+type detachFailingClient struct {
+	pritunl.Client
+	calls *[]string
+}
+
+func (c detachFailingClient) StopServer(serverId string) error {
+	*c.calls = append(*c.calls, "stop "+serverId)
+	return nil
+}
+
+func (c detachFailingClient) StartServer(serverId string) error {
+	*c.calls = append(*c.calls, "start "+serverId)
+	return nil
+}
+
+func (c detachFailingClient) DetachOrganizationFromServer(organizationId, serverId string) error {
+	return errors.New("Non-200 response on detaching the organization")
+}
+
+func TestDetachOrganizationFromServerRestartsOnError(t *testing.T) {
+	var calls []string
+	server := pritunl.Server{ID: "server", Name: "prod", Status: pritunl.ServerStatusOnline}
+
+	diags := detachOrganizationFromServer(detachFailingClient{calls: &calls}, "organization", server)
+	if !diags.HasError() {
+		t.Fatal("expected an error diagnostic")
+	}
+	if expected := []string{"stop server", "start server"}; !reflect.DeepEqual(calls, expected) {
+		t.Errorf("expected the server to be started again, got %q", calls)
+	}
+}
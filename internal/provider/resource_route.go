@@ -4,9 +4,8 @@ import (
 	"fmt"
 	"context"
 	"sync"
-	"strings"
 
-	"github.com/maulid7/terraform-provider-pritunl/internal/pritunl"
+	"github.com/maulid7/terraform-provider-pritunl/pritunl"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -69,18 +68,57 @@ func resourceRoute() *schema.Resource {
 		ReadContext: resourceReadRoute,
 		UpdateContext: resourceUpdateRoute,
 		DeleteContext: resourceDeleteRoute,
+		CustomizeDiff: resourceRouteCustomizeDiff,
 		Importer: &schema.ResourceImporter{
 			StateContext: resourceRouteImport,
 		},
 	}
 }
 
+// Detects duplicate routes on the server at plan time
+func resourceRouteCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	apiClient, ok := meta.(pritunl.Client)
+	if !ok {
+		return nil
+	}
+	apiClient = requestClient(ctx, apiClient)
+
+	// the server can be created in the same plan
+	if !d.NewValueKnown("server_id") || !d.NewValueKnown("network") {
+		return nil
+	}
+
+	if d.Id() != "" && !d.HasChange("network") {
+		return nil
+	}
+
+	serverId := d.Get("server_id").(string)
+	network := d.Get("network").(string)
+
+	routes, err := apiClient.GetRoutesByServer(serverId)
+	if err != nil {
+		return fmt.Errorf("failed to get routes for the duplicate validation: %s", err)
+	}
+
+	for _, route := range routes {
+		if route.VirtualNetwork {
+			continue
+		}
+
+		if sameNetwork(route.Network, network) {
+			return fmt.Errorf("the route %s already exists on the server %s", route.Network, serverId)
+		}
+	}
+
+	return nil
+}
+
 func resourceCreateRoute(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	resourceMutex.Lock()
 	defer resourceMutex.Unlock()
 
 	// fmt.Println("CREATE CALLED")
-	apiClient := meta.(pritunl.Client)
+	apiClient := requestClient(ctx, meta)
 
 	serverId := d.Get("server_id").(string)
 
@@ -114,9 +152,8 @@ func resourceCreateRoute(ctx context.Context, d *schema.ResourceData, meta inter
 	}
 
 	if shouldServerBeStarted {
-		err = apiClient.StartServer(d.Get("server_id").(string))
-		if err != nil {
-			return diag.Errorf("Error on starting server: %s", err)
+		if diags := startServer(apiClient, d.Get("server_id").(string)); diags.HasError() {
+			return diags
 		}
 	}
 
@@ -134,7 +171,7 @@ func resourceReadRoute(ctx context.Context, d *schema.ResourceData, meta interfa
 	defer resourceMutex.RUnlock()
 
 	// fmt.Println("READ CALLED")
-	apiClient := meta.(pritunl.Client)
+	apiClient := requestClient(ctx, meta)
 
 	routes, err := apiClient.GetRoutesByServer(d.Get("server_id").(string))
 	if err != nil {
@@ -156,7 +193,7 @@ func resourceUpdateRoute(ctx context.Context, d *schema.ResourceData, meta inter
 	defer resourceMutex.Unlock()
 
 	// fmt.Println("UPDATE CALLED")
-	apiClient := meta.(pritunl.Client)
+	apiClient := requestClient(ctx, meta)
 	
 	server, err := apiClient.GetServer(d.Get("server_id").(string))
 	if err != nil {
@@ -201,9 +238,8 @@ func resourceUpdateRoute(ctx context.Context, d *schema.ResourceData, meta inter
 	}
 
 	if shouldServerBeStarted {
-		err = apiClient.StartServer(d.Get("server_id").(string))
-		if err != nil {
-			return diag.Errorf("Error on starting server: %s", err)
+		if diags := startServer(apiClient, d.Get("server_id").(string)); diags.HasError() {
+			return diags
 		}
 	}
 
@@ -215,7 +251,7 @@ func resourceDeleteRoute(ctx context.Context, d *schema.ResourceData, meta inter
 	defer resourceMutex.Unlock()
 
 	// fmt.Println("DELETE CALLED")
-	apiClient := meta.(pritunl.Client)
+	apiClient := requestClient(ctx, meta)
 
 	server, err := apiClient.GetServer(d.Get("server_id").(string))
 	if err != nil {
@@ -243,9 +279,8 @@ func resourceDeleteRoute(ctx context.Context, d *schema.ResourceData, meta inter
 	}
 
 	if shouldServerBeStarted {
-		err = apiClient.StartServer(d.Get("server_id").(string))
-		if err != nil {
-			return diag.Errorf("Error on starting server: %s", err)
+		if diags := startServer(apiClient, d.Get("server_id").(string)); diags.HasError() {
+			return diags
 		}
 	}
 
@@ -254,26 +289,27 @@ func resourceDeleteRoute(ctx context.Context, d *schema.ResourceData, meta inter
 	return nil
 }
 
+// Imports by server:${name}/route:${network} or ${serverId}/${routeId}, the legacy ${serverId}-${routeId} format is supported too
 func resourceRouteImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-	apiClient := meta.(pritunl.Client)
+	apiClient := requestClient(ctx, meta)
 
-	attributes := strings.Split(d.Id(), "-")
-	if len(attributes) < 2 {
-		return nil, fmt.Errorf("invalid format: expected ${serverId}-${routeId}, e.g. 60cd0be07723cf3c9114686c-60cd0be17723cf3c91146873, actual id is %s", d.Id())
+	values, err := parseImportId(d.Id(), "server", "route")
+	if err != nil {
+		return nil, err
 	}
 
-	serverId := attributes[0]
-	routeId := attributes[1]
-
-	d.SetId(routeId)
-	d.Set("server_id", serverId)
+	server, err := resolveServer(apiClient, values[0])
+	if err != nil {
+		return nil, err
+	}
 
-	routes, err := apiClient.GetRoutesByServer(d.Get("server_id").(string))
+	route, err := resolveRoute(apiClient, server.ID, values[1])
 	if err != nil {
 		return nil, err
 	}
 
-	_ = getRouteFromList(d.Id(), routes)
+	d.SetId(route.ID)
+	d.Set("server_id", server.ID)
 
 	return []*schema.ResourceData{d}, nil
 }
@@ -55,6 +55,28 @@ func TestAccPritunlRoute(t *testing.T) {
 		})
 	})
 
+	t.Run("creates a duplicate route with error", func(t *testing.T) {
+		serverName := "tfacc-server1"
+		route := "8.8.8.8/32"
+
+		resource.Test(t, resource.TestCase{
+			PreCheck: func() {
+				preCheck(t)
+			},
+			ProviderFactories: providerFactories,
+			CheckDestroy:      testResourceDestroy("pritunl_server"),
+			Steps: []resource.TestStep{
+				{
+					Config: testPritunlRouteSimpleConfig(serverName, route),
+				},
+				{
+					Config:      testPritunlDuplicateRoute(serverName, route),
+					ExpectError: regexp.MustCompile(fmt.Sprintf("the route %s already exists on the server", route)),
+				},
+			},
+		})
+	})
+
 	t.Run("creates a route on a test server", func(t *testing.T) {
 		serverName := "tfacc-server1"
 		route := "8.8.8.8/32"
@@ -76,6 +98,7 @@ func TestAccPritunlRoute(t *testing.T) {
 				},
 				// import test
 				pritunlRouteImportStep("pritunl_route.test"),
+				namedImportStep("pritunl_route.test", fmt.Sprintf("server:%s/route:%s", serverName, route), "nat"),
 				{
 					Config: testPritunlServerWithoutRouteConfig(serverName),
 					Check: resource.ComposeTestCheckFunc(
@@ -325,6 +348,22 @@ func testPritunlMultipleRoute(serverName string, networks map[string]interface{}
 		%[2]s`, serverName, routeResource)
 }
 
+func testPritunlDuplicateRoute(serverName string, network string) string {
+	return fmt.Sprintf(`
+		resource "pritunl_server" "test" {
+			name	= "%[1]s"
+		}
+		resource "pritunl_route" "test" {
+			server_id    = pritunl_server.test.id
+			network		 = "%[2]s"
+		}
+		resource "pritunl_route" "duplicate" {
+			server_id    = pritunl_server.test.id
+			network		 = "%[2]s"
+		}
+	`, serverName, network)
+}
+
 func testPritunlRouteDestroy(s *terraform.State) error {
 	serverId := s.RootModule().Resources["pritunl_server.test"].Primary.Attributes["id"]
 	fmt.Println(serverId)
@@ -6,7 +6,7 @@ import (
 	"net"
 	"strings"
 
-	"github.com/maulid7/terraform-provider-pritunl/internal/pritunl"
+	"github.com/maulid7/terraform-provider-pritunl/pritunl"
 	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -202,7 +202,7 @@ func resourceServer() *schema.Resource {
 				Type:        schema.TypeBool,
 				Required:    false,
 				Optional:    true,
-				Description: "Enables two-step authentication using Google Authenticator. Verification code is entered as the user password when connecting",
+				Description: "Enables two-step authentication using Google Authenticator. Verification code is entered as the user password when connecting. On WireGuard the verification code is asked by the Pritunl Client, native WireGuard clients can't connect to the server with otp_auth enabled",
 			},
 			"device_auth": {
 				Type:        schema.TypeBool,
@@ -400,6 +400,37 @@ func resourceServer() *schema.Resource {
 				Optional:    true,
 				Description: "Use VXLan for routing client-to-client traffic with replicated servers.",
 			},
+			"ipv6_firewall": {
+				Type:        schema.TypeBool,
+				Required:    false,
+				Optional:    true,
+				Computed:    true,
+				Description: "Filter IPv6 traffic to the server and clients, only routed IPv6 traffic is allowed.",
+			},
+			"lzo_compression": {
+				Type:        schema.TypeBool,
+				Required:    false,
+				Optional:    true,
+				Description: "Enable LZO compression on the server.",
+				Deprecated:  "LZO compression is deprecated by Pritunl and is not supported by recent clients, it will be removed in a future version.",
+			},
+			"jumbo_frames": {
+				Type:        schema.TypeBool,
+				Required:    false,
+				Optional:    true,
+				Description: "Enable jumbo frames on the server, requires a network with jumbo frames support.",
+			},
+			"route_dns": {
+				Type:        schema.TypeBool,
+				Required:    false,
+				Optional:    true,
+				Description: "Route DNS traffic of the clients through the VPN.",
+			},
+			"wg": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Shows if WireGuard is enabled on the server, it is enabled when network_wg and port_wg are set.",
+			},
 			"organization_ids": {
 				Type: schema.TypeList,
 				Elem: &schema.Schema{
@@ -419,13 +450,18 @@ func resourceServer() *schema.Resource {
 				Computed:    true,
 				Description: "The list of attached hosts to the server",
 			},
+			"manage_attachments": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Manage the attached organizations and hosts with the organization_ids and host_ids attributes. Set to false when the attachments are managed with the pritunl_server_organization_attachment and pritunl_server_host_attachment resources. The server has to be created offline then, the attachment resources are created after it",
+			},
 			"status": {
 				Type:         schema.TypeString,
 				Required:     false,
 				Optional:     true,
 				Computed:     true,
 				Description:  "The status of the server",
-				RequiredWith: []string{"organization_ids"},
 				ValidateDiagFunc: func(v interface{}, path cty.Path) diag.Diagnostics {
 					allowedStatusesMap := map[string]struct{}{
 						pritunl.ServerStatusOffline: {},
@@ -456,15 +492,202 @@ func resourceServer() *schema.Resource {
 		ReadContext:   resourceReadServer,
 		UpdateContext: resourceUpdateServer,
 		DeleteContext: resourceDeleteServer,
+		CustomizeDiff: resourceServerCustomizeDiff,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: resourceServerImport,
 		},
 	}
 }
 
+// Imports by server:${name} or the ID
+func resourceServerImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	apiClient := requestClient(ctx, meta)
+
+	values, err := parseImportId(d.Id(), "server")
+	if err != nil {
+		return nil, err
+	}
+
+	server, err := resolveServer(apiClient, values[0])
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(server.ID)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// Detects network and port conflicts with other servers at plan time, Pritunl rejects them only on apply
+func resourceServerCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	err := validateServerBridgeNetwork(d)
+	if err != nil {
+		return err
+	}
+
+	err = validateServerAttachments(d)
+	if err != nil {
+		return err
+	}
+
+	apiClient, ok := meta.(pritunl.Client)
+	if !ok {
+		return nil
+	}
+	apiClient = requestClient(ctx, apiClient)
+
+	if d.Id() != "" && !d.HasChanges("network", "network_wg", "port", "port_wg", "protocol", "host_ids") {
+		return nil
+	}
+
+	servers, err := apiClient.GetServers()
+	if err != nil {
+		return fmt.Errorf("failed to get servers for the conflict validation: %s", err)
+	}
+
+	conflicts := make([]string, 0)
+
+	networks := make(map[string]string)
+	for _, key := range []string{"network", "network_wg"} {
+		if !d.NewValueKnown(key) {
+			continue
+		}
+		if network := d.Get(key).(string); network != "" {
+			networks[key] = network
+		}
+	}
+
+	if networks["network"] != "" && networksOverlap(networks["network"], networks["network_wg"]) {
+		conflicts = append(conflicts, fmt.Sprintf("network %s overlaps with network_wg %s", networks["network"], networks["network_wg"]))
+	}
+
+	for _, server := range servers {
+		if server.ID == d.Id() {
+			continue
+		}
+
+		for key, network := range networks {
+			for _, serverNetwork := range []string{server.Network, server.NetworkWG} {
+				if networksOverlap(network, serverNetwork) {
+					conflicts = append(conflicts, fmt.Sprintf("%s %s overlaps with network %s of the server %s", key, network, serverNetwork, server.Name))
+				}
+			}
+		}
+	}
+
+	if !d.NewValueKnown("port") || !d.NewValueKnown("port_wg") || !d.NewValueKnown("host_ids") {
+		return serverConflictsError(conflicts)
+	}
+
+	hostIds := make(map[string]struct{})
+	for _, v := range d.Get("host_ids").([]interface{}) {
+		if hostId, ok := v.(string); ok && hostId != "" {
+			hostIds[hostId] = struct{}{}
+		}
+	}
+
+	// Hosts attached by default are unknown until the server is created
+	if len(hostIds) == 0 {
+		return serverConflictsError(conflicts)
+	}
+
+	endpoints := serverEndpoints(d.Get("protocol").(string), d.Get("port").(int), d.Get("network_wg").(string) != "", d.Get("port_wg").(int))
+
+	for _, server := range servers {
+		if server.ID == d.Id() {
+			continue
+		}
+
+		sharedEndpoints := make([]serverEndpoint, 0)
+		for _, endpoint := range endpoints {
+			for _, serverEndpoint := range serverEndpoints(server.Protocol, server.Port, server.WG, server.PortWG) {
+				if endpoint == serverEndpoint {
+					sharedEndpoints = append(sharedEndpoints, endpoint)
+				}
+			}
+		}
+
+		if len(sharedEndpoints) == 0 {
+			continue
+		}
+
+		hosts, err := apiClient.GetHostsByServer(server.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get hosts of the server %s for the conflict validation: %s", server.Name, err)
+		}
+
+		for _, host := range hosts {
+			if _, ok := hostIds[host.ID]; !ok {
+				continue
+			}
+
+			for _, endpoint := range sharedEndpoints {
+				conflicts = append(conflicts, fmt.Sprintf("port %d/%s is already used by the server %s on the host %s", endpoint.port, endpoint.protocol, server.Name, host.Name))
+			}
+		}
+	}
+
+	return serverConflictsError(conflicts)
+}
+
+func validateServerAttachments(d *schema.ResourceDiff) error {
+	organizationIds := d.Get("organization_ids").([]interface{})
+
+	if !d.Get("manage_attachments").(bool) {
+		if len(organizationIds) > 0 {
+			return fmt.Errorf("organization_ids can't be set when manage_attachments is false, use pritunl_server_organization_attachment resources instead")
+		}
+
+		// host_ids is computed, an unchanged value is the one read before manage_attachments was disabled
+		if d.HasChange("host_ids") && len(d.Get("host_ids").([]interface{})) > 0 {
+			return fmt.Errorf("host_ids can't be set when manage_attachments is false, use pritunl_server_host_attachment resources instead")
+		}
+
+		// the attachment resources depend on the server, so it has no organizations when it is created
+		if d.Id() == "" && d.Get("status").(string) == pritunl.ServerStatusOnline {
+			return fmt.Errorf("the server can't be created online when manage_attachments is false, create it offline and set status = %q once the attachment resources exist", pritunl.ServerStatusOnline)
+		}
+
+		return nil
+	}
+
+	// a server without organizations can't be started
+	if d.HasChange("status") && d.Get("status").(string) == pritunl.ServerStatusOnline && d.NewValueKnown("organization_ids") && len(organizationIds) == 0 {
+		return fmt.Errorf("the server can be online only with organization_ids, unless manage_attachments is false")
+	}
+
+	return nil
+}
+
+func validateServerBridgeNetwork(d *schema.ResourceDiff) error {
+	if d.Get("network_mode").(string) != pritunl.ServerNetworkModeBridge {
+		return nil
+	}
+
+	if !d.NewValueKnown("network") || !d.NewValueKnown("network_start") || !d.NewValueKnown("network_end") {
+		return nil
+	}
+
+	// the same validation as on apply, reported at plan time
+	return pritunl.ValidateServer(&pritunl.Server{
+		NetworkMode:  pritunl.ServerNetworkModeBridge,
+		Network:      d.Get("network").(string),
+		NetworkStart: d.Get("network_start").(string),
+		NetworkEnd:   d.Get("network_end").(string),
+	})
+}
+
+func serverConflictsError(conflicts []string) error {
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("the server conflicts with existing Pritunl servers:\n%s", strings.Join(conflicts, "\n"))
+}
+
 // Uses for importing
 func resourceReadServer(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	apiClient := meta.(pritunl.Client)
+	apiClient := requestClient(ctx, meta)
 
 	server, err := apiClient.GetServer(d.Id())
 	if err != nil {
@@ -522,9 +745,22 @@ func resourceReadServer(ctx context.Context, d *schema.ResourceData, meta interf
 	d.Set("dns_mapping", server.DnsMapping)
 	d.Set("inter_client", server.InterClient)
 	d.Set("vxlan", server.VxLan)
+	d.Set("ipv6_firewall", server.IPv6Firewall)
+	d.Set("lzo_compression", server.LzoCompression)
+	d.Set("jumbo_frames", server.JumboFrames)
+	d.Set("route_dns", server.RouteDns)
+	d.Set("wg", server.WG)
 	d.Set("status", server.Status)
 
-	if len(organizations) > 0 {
+	manageAttachments := manageServerAttachments(d)
+	d.Set("manage_attachments", manageAttachments)
+
+	if !manageAttachments {
+		d.Set("organization_ids", nil)
+		d.Set("host_ids", nil)
+	}
+
+	if manageAttachments && len(organizations) > 0 {
 		organizationsList := make([]string, 0)
 
 		if organizations != nil {
@@ -564,7 +800,7 @@ func resourceReadServer(ctx context.Context, d *schema.ResourceData, meta interf
 		d.Set("groups", groupsList)
 	}
 
-	if len(hosts) > 0 {
+	if manageAttachments && len(hosts) > 0 {
 		hostsList := make([]string, 0)
 
 		if hosts != nil {
@@ -589,59 +825,75 @@ func resourceReadServer(ctx context.Context, d *schema.ResourceData, meta interf
 }
 
 func resourceCreateServer(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	apiClient := meta.(pritunl.Client)
-
-	serverData := map[string]interface{}{
-		"name":               d.Get("name"),
-		"protocol":           d.Get("protocol"),
-		"port":               d.Get("port"),
-		"network":            d.Get("network"),
-		"cipher":             d.Get("cipher"),
-		"hash":               d.Get("hash"),
-		"bind_address":       d.Get("bind_address"),
-		"groups":             d.Get("groups"),
-		"dns_servers":        d.Get("dns_servers"),
-		"network_wg":         d.Get("network_wg"),
-		"port_wg":            d.Get("port_wg"),
-		"sso_auth":           d.Get("sso_auth"),
-		"otp_auth":           d.Get("otp_auth"),
-		"device_auth":        d.Get("device_auth"),
-		"dynamic_firewall":   d.Get("dynamic_firewall"),
-		"ipv6":               d.Get("ipv6"),
-		"dh_param_bits":      d.Get("dh_param_bits"),
-		"ping_interval":      d.Get("ping_interval"),
-		"ping_timeout":       d.Get("ping_timeout"),
-		"link_ping_interval": d.Get("link_ping_interval"),
-		"link_ping_timeout":  d.Get("link_ping_timeout"),
-		"session_timeout":    d.Get("session_timeout"),
-		"inactive_timeout":   d.Get("inactive_timeout"),
-		"max_clients":        d.Get("max_clients"),
-		"network_mode":       d.Get("network_mode"),
-		"network_start":      d.Get("network_start"),
-		"network_end":        d.Get("network_end"),
-		"mss_fix":            d.Get("mss_fix"),
-		"max_devices":        d.Get("max_devices"),
-		"pre_connect_msg":    d.Get("pre_connect_msg"),
-		"allowed_devices":    d.Get("allowed_devices"),
-		"search_domain":      d.Get("search_domain"),
-		"replica_count":      d.Get("replica_count"),
-		"multi_device":       d.Get("multi_device"),
-		"debug":              d.Get("debug"),
-		"restrict_routes":    d.Get("restrict_routes"),
-		"block_outside_dns":  d.Get("block_outside_dns"),
-		"dns_mapping":        d.Get("dns_mapping"),
-		"inter_client":       d.Get("inter_client"),
-		"vxlan":              d.Get("vxlan"),
-	}
-
-	server, err := apiClient.CreateServer(serverData)
+	apiClient := requestClient(ctx, meta)
+
+	serverRequest := pritunl.ServerCreateRequest{
+		Name:             d.Get("name").(string),
+		Protocol:         d.Get("protocol").(string),
+		Port:             d.Get("port").(int),
+		Network:          d.Get("network").(string),
+		Cipher:           d.Get("cipher").(string),
+		Hash:             d.Get("hash").(string),
+		BindAddress:      d.Get("bind_address").(string),
+		Groups:           expandStringList(d.Get("groups")),
+		DnsServers:       expandStringList(d.Get("dns_servers")),
+		NetworkWG:        d.Get("network_wg").(string),
+		PortWG:           d.Get("port_wg").(int),
+		SsoAuth:          d.Get("sso_auth").(bool),
+		OtpAuth:          d.Get("otp_auth").(bool),
+		DeviceAuth:       d.Get("device_auth").(bool),
+		DynamicFirewall:  d.Get("dynamic_firewall").(bool),
+		IPv6:             d.Get("ipv6").(bool),
+		DhParamBits:      d.Get("dh_param_bits").(int),
+		PingInterval:     d.Get("ping_interval").(int),
+		PingTimeout:      d.Get("ping_timeout").(int),
+		LinkPingInterval: d.Get("link_ping_interval").(int),
+		LinkPingTimeout:  d.Get("link_ping_timeout").(int),
+		SessionTimeout:   d.Get("session_timeout").(int),
+		InactiveTimeout:  d.Get("inactive_timeout").(int),
+		MaxClients:       d.Get("max_clients").(int),
+		NetworkMode:      d.Get("network_mode").(string),
+		NetworkStart:     d.Get("network_start").(string),
+		NetworkEnd:       d.Get("network_end").(string),
+		MssFix:           d.Get("mss_fix").(int),
+		MaxDevices:       d.Get("max_devices").(int),
+		PreConnectMsg:    d.Get("pre_connect_msg").(string),
+		AllowedDevices:   d.Get("allowed_devices").(string),
+		SearchDomain:     d.Get("search_domain").(string),
+		ReplicaCount:     d.Get("replica_count").(int),
+		MultiDevice:      d.Get("multi_device").(bool),
+		Debug:            d.Get("debug").(bool),
+		RestrictRoutes:   d.Get("restrict_routes").(bool),
+		BlockOutsideDns:  d.Get("block_outside_dns").(bool),
+		DnsMapping:       d.Get("dns_mapping").(bool),
+		InterClient:      d.Get("inter_client").(bool),
+		VxLan:            d.Get("vxlan").(bool),
+		LzoCompression:   d.Get("lzo_compression").(bool),
+		JumboFrames:      d.Get("jumbo_frames").(bool),
+		RouteDns:         d.Get("route_dns").(bool),
+	}
+
+	// ipv6_firewall is enabled by Pritunl by default, so an explicit false has to be sent
+	if v, ok := d.GetOkExists("ipv6_firewall"); ok {
+		serverRequest.IPv6Firewall = v.(bool)
+		serverRequest.ForceSendFields = append(serverRequest.ForceSendFields, "ipv6_firewall")
+	}
+
+	err := pritunl.ValidateServer(serverRequest.Server())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	server, err := apiClient.CreateServer(serverRequest)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
 	d.SetId(server.ID)
 
-	if d.HasChange("organization_ids") {
+	manageAttachments := d.Get("manage_attachments").(bool)
+
+	if manageAttachments && d.HasChange("organization_ids") {
 		_, newOrgs := d.GetChange("organization_ids")
 		for _, v := range newOrgs.([]interface{}) {
 			err = apiClient.AttachOrganizationToServer(v.(string), d.Id())
@@ -661,7 +913,7 @@ func resourceCreateServer(ctx context.Context, d *schema.ResourceData, meta inte
 		return diag.Errorf("Error on attaching server to the organization: %s", err)
 	}
 
-	if d.HasChange("host_ids") {
+	if manageAttachments && d.HasChange("host_ids") {
 		// delete default host(s) only when host_ids aren't empty
 
 		hosts, err := apiClient.GetHostsByServer(d.Id())
@@ -685,9 +937,8 @@ func resourceCreateServer(ctx context.Context, d *schema.ResourceData, meta inte
 	}
 
 	if d.Get("status").(string) == pritunl.ServerStatusOnline {
-		err = apiClient.StartServer(d.Id())
-		if err != nil {
-			return diag.Errorf("Error on starting server: %s", err)
+		if diags := startServer(apiClient, d.Id()); diags.HasError() {
+			return diags
 		}
 	}
 
@@ -695,7 +946,7 @@ func resourceCreateServer(ctx context.Context, d *schema.ResourceData, meta inte
 }
 
 func resourceUpdateServer(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	apiClient := meta.(pritunl.Client)
+	apiClient := requestClient(ctx, meta)
 
 	server, err := apiClient.GetServer(d.Id())
 	if err != nil {
@@ -807,10 +1058,6 @@ func resourceUpdateServer(ctx context.Context, d *schema.ResourceData, meta inte
 		server.NetworkEnd = d.Get("network_end").(string)
 	}
 
-	if server.NetworkMode == pritunl.ServerNetworkModeBridge && (server.NetworkStart == "" || server.NetworkEnd == "") {
-		return diag.Errorf("the attribute network_mode = %s requires network_start and network_end attributes", pritunl.ServerNetworkModeBridge)
-	}
-
 	if d.HasChange("mss_fix") {
 		server.MssFix = d.Get("mss_fix").(int)
 	}
@@ -855,10 +1102,30 @@ func resourceUpdateServer(ctx context.Context, d *schema.ResourceData, meta inte
 		server.DnsMapping = d.Get("dns_mapping").(bool)
 	}
 
+	if d.HasChange("inter_client") {
+		server.InterClient = d.Get("inter_client").(bool)
+	}
+
 	if d.HasChange("vxlan") {
 		server.VxLan = d.Get("vxlan").(bool)
 	}
 
+	if d.HasChange("ipv6_firewall") {
+		server.IPv6Firewall = d.Get("ipv6_firewall").(bool)
+	}
+
+	if d.HasChange("lzo_compression") {
+		server.LzoCompression = d.Get("lzo_compression").(bool)
+	}
+
+	if d.HasChange("jumbo_frames") {
+		server.JumboFrames = d.Get("jumbo_frames").(bool)
+	}
+
+	if d.HasChange("route_dns") {
+		server.RouteDns = d.Get("route_dns").(bool)
+	}
+
 	if d.HasChange("groups") {
 		groups := make([]string, 0)
 		for _, v := range d.Get("groups").([]interface{}) {
@@ -875,13 +1142,23 @@ func resourceUpdateServer(ctx context.Context, d *schema.ResourceData, meta inte
 		server.DnsServers = dnsServers
 	}
 
+	// changed attributes have to reach the API even when they are cleared
+	server.ForceSendFields = changedAttributes(d, serverAttributes...)
+
+	err = pritunl.ValidateServer(server)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
 	// Stop server before applying any change
 	err = apiClient.StopServer(d.Id())
 	if err != nil {
 		return diag.Errorf("Error on stopping server: %s", err)
 	}
 
-	if d.HasChange("organization_ids") {
+	manageAttachments := d.Get("manage_attachments").(bool)
+
+	if manageAttachments && d.HasChange("organization_ids") {
 		oldOrgs, newOrgs := d.GetChange("organization_ids")
 
 		oldOrgsOnly := diffStringLists(oldOrgs.([]interface{}), newOrgs.([]interface{}))
@@ -901,7 +1178,7 @@ func resourceUpdateServer(ctx context.Context, d *schema.ResourceData, meta inte
 		}
 	}
 
-	if d.HasChange("host_ids") {
+	if manageAttachments && d.HasChange("host_ids") {
 		oldHosts, newHosts := d.GetChange("host_ids")
 		for _, v := range oldHosts.([]interface{}) {
 			err = apiClient.DetachHostFromServer(v.(string), d.Id())
@@ -927,9 +1204,8 @@ func resourceUpdateServer(ctx context.Context, d *schema.ResourceData, meta inte
 	}
 
 	if shouldServerBeStarted {
-		err = apiClient.StartServer(d.Id())
-		if err != nil {
-			return diag.Errorf("Error on starting server: %s", err)
+		if diags := startServer(apiClient, d.Id()); diags.HasError() {
+			return diags
 		}
 	}
 
@@ -937,7 +1213,7 @@ func resourceUpdateServer(ctx context.Context, d *schema.ResourceData, meta inte
 }
 
 func resourceDeleteServer(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	apiClient := meta.(pritunl.Client)
+	apiClient := requestClient(ctx, meta)
 
 	err := apiClient.DeleteServer(d.Id())
 	if err != nil {
@@ -949,6 +1225,127 @@ func resourceDeleteServer(ctx context.Context, d *schema.ResourceData, meta inte
 	return nil
 }
 
+// Server attributes sent to the Pritunl API as is, the key matches the JSON attribute name
+var serverAttributes = []string{
+	"name", "protocol", "cipher", "hash", "port", "network", "bind_address", "groups", "dns_servers",
+	"network_wg", "port_wg", "sso_auth", "otp_auth", "device_auth", "dynamic_firewall", "ipv6",
+	"dh_param_bits", "ping_interval", "ping_timeout", "link_ping_interval", "link_ping_timeout",
+	"session_timeout", "inactive_timeout", "max_clients", "network_mode", "network_start", "network_end",
+	"mss_fix", "max_devices", "pre_connect_msg", "allowed_devices", "search_domain", "replica_count",
+	"multi_device", "debug", "restrict_routes", "block_outside_dns", "dns_mapping", "inter_client", "vxlan",
+	"ipv6_firewall", "lzo_compression", "jumbo_frames", "route_dns",
+}
+
+// manageServerAttachments reports whether organization_ids and host_ids are managed,
+// states written before manage_attachments existed manage them
+func manageServerAttachments(d *schema.ResourceData) bool {
+	v, ok := d.GetOkExists("manage_attachments")
+	return !ok || v.(bool)
+}
+
+// withServerStopped stops an online server while the change is applied and starts it again afterwards
+func withServerStopped(apiClient pritunl.Client, serverId string, change func() error) diag.Diagnostics {
+	server, err := apiClient.GetServer(serverId)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	online := server.Status == pritunl.ServerStatusOnline
+
+	if online {
+		err = apiClient.StopServer(serverId)
+		if err != nil {
+			return diag.Errorf("Error on stopping server: %s", err)
+		}
+	}
+
+	err = change()
+	if err != nil {
+		// the change failed, but the server should still be running as before
+		diags := diag.FromErr(err)
+		if online {
+			diags = append(diags, startServer(apiClient, serverId)...)
+		}
+		return diags
+	}
+
+	if online {
+		return startServer(apiClient, serverId)
+	}
+
+	return nil
+}
+
+// Number of server output lines included in the diagnostic of a failed server start
+const serverOutputTailLines = 20
+
+// startServer starts the server and explains a failure with the tail of the server output
+func startServer(apiClient pritunl.Client, serverId string) diag.Diagnostics {
+	err := apiClient.StartServer(serverId)
+	if err == nil {
+		return nil
+	}
+
+	return diag.Diagnostics{
+		{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("Error on starting server: %s", err),
+			Detail:   serverOutputDetail(apiClient, serverId),
+		},
+	}
+}
+
+func serverOutputDetail(apiClient pritunl.Client, serverId string) string {
+	output, err := apiClient.GetServerOutput(serverId)
+	if err != nil {
+		return fmt.Sprintf("Could not get the server output: %s", err)
+	}
+
+	lines := tailLines(output.Output, serverOutputTailLines)
+	if len(lines) == 0 {
+		return "The server output is empty"
+	}
+
+	return fmt.Sprintf("Last %d lines of the server output:\n%s", len(lines), strings.Join(lines, "\n"))
+}
+
+func tailLines(lines []string, count int) []string {
+	if count > 0 && len(lines) > count {
+		return lines[len(lines)-count:]
+	}
+
+	return lines
+}
+
+func changedAttributes(d *schema.ResourceData, keys ...string) []string {
+	result := make([]string, 0)
+
+	for _, key := range keys {
+		if d.HasChange(key) {
+			result = append(result, key)
+		}
+	}
+
+	return result
+}
+
+func expandStringList(v interface{}) []string {
+	result := make([]string, 0)
+
+	list, ok := v.([]interface{})
+	if !ok {
+		return result
+	}
+
+	for _, item := range list {
+		if value, ok := item.(string); ok {
+			result = append(result, value)
+		}
+	}
+
+	return result
+}
+
 func diffStringLists(mainList []interface{}, otherList []interface{}) []string {
 	result := make([]string, 0)
 	var found bool
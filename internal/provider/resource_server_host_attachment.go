@@ -0,0 +1,180 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/maulid7/terraform-provider-pritunl/pritunl"
+)
+
+func resourceServerHostAttachment() *schema.Resource {
+	return &schema.Resource{
+		Description: "The server host attachment resource attaches a host to a Pritunl server. An online server is restarted to apply the change. Use it with manage_attachments = false on the pritunl_server resource, Pritunl attaches the default host to new servers.",
+		Schema: map[string]*schema.Schema{
+			"server_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the server",
+			},
+			"host_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the host to attach",
+			},
+		},
+		CreateContext: resourceCreateServerHostAttachment,
+		ReadContext:   resourceReadServerHostAttachment,
+		DeleteContext: resourceDeleteServerHostAttachment,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceServerHostAttachmentImport,
+		},
+	}
+}
+
+func resourceReadServerHostAttachment(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	resourceMutex.RLock()
+	defer resourceMutex.RUnlock()
+
+	apiClient := requestClient(ctx, meta)
+
+	hosts, err := apiClient.GetHostsByServer(d.Get("server_id").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	for _, host := range hosts {
+		if host.ID == d.Get("host_id").(string) {
+			return nil
+		}
+	}
+
+	// the host was detached outside of Terraform
+	d.SetId("")
+
+	return nil
+}
+
+func resourceCreateServerHostAttachment(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	resourceMutex.Lock()
+	defer resourceMutex.Unlock()
+
+	apiClient := requestClient(ctx, meta)
+
+	serverId := d.Get("server_id").(string)
+	hostId := d.Get("host_id").(string)
+
+	diags := withServerStopped(apiClient, serverId, func() error {
+		err := apiClient.AttachHostToServer(hostId, serverId)
+		if err != nil {
+			return fmt.Errorf("Error on attaching a host to the server: %s", err)
+		}
+		return nil
+	})
+	if diags.HasError() {
+		return diags
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", serverId, hostId))
+
+	return diags
+}
+
+func resourceDeleteServerHostAttachment(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	resourceMutex.Lock()
+	defer resourceMutex.Unlock()
+
+	apiClient := requestClient(ctx, meta)
+
+	serverId := d.Get("server_id").(string)
+	hostId := d.Get("host_id").(string)
+
+	hosts, err := apiClient.GetHostsByServer(serverId)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// a server without hosts can't be started, so it is left offline after detaching the last host
+	if len(hosts) == 1 && hosts[0].ID == hostId {
+		server, err := apiClient.GetServer(serverId)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		online := server.Status == pritunl.ServerStatusOnline
+
+		if online {
+			err = apiClient.StopServer(serverId)
+			if err != nil {
+				return diag.Errorf("Error on stopping server: %s", err)
+			}
+		}
+
+		err = apiClient.DetachHostFromServer(hostId, serverId)
+		if err != nil {
+			// the host is still attached, so the server can run as before
+			diags := diag.Errorf("Error on detaching a host from the server: %s", err)
+			if online {
+				diags = append(diags, startServer(apiClient, serverId)...)
+			}
+			return diags
+		}
+
+		d.SetId("")
+
+		if online {
+			return diag.Diagnostics{
+				{
+					Severity: diag.Warning,
+					Summary:  fmt.Sprintf("Server %s was left offline", server.Name),
+					Detail:   "The server has no hosts attached after detaching the deleted host, so it can't be started.",
+				},
+			}
+		}
+
+		return nil
+	}
+
+	diags := withServerStopped(apiClient, serverId, func() error {
+		err := apiClient.DetachHostFromServer(hostId, serverId)
+		if err != nil {
+			return fmt.Errorf("Error on detaching a host from the server: %s", err)
+		}
+		return nil
+	})
+	if diags.HasError() {
+		return diags
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+// Imports by server:${name}/host:${name} or ${serverId}/${hostId}
+func resourceServerHostAttachmentImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	apiClient := requestClient(ctx, meta)
+
+	values, err := parseImportId(d.Id(), "server", "host")
+	if err != nil {
+		return nil, err
+	}
+
+	server, err := resolveServer(apiClient, values[0])
+	if err != nil {
+		return nil, err
+	}
+
+	host, err := resolveHost(apiClient, values[1])
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", server.ID, host.ID))
+	d.Set("server_id", server.ID)
+	d.Set("host_id", host.ID)
+
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,163 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/maulid7/terraform-provider-pritunl/pritunl"
+)
+
+func TestAccPritunlServerHostAttachment(t *testing.T) {
+
+	t.Run("attaches a host to a server", func(t *testing.T) {
+		serverName := "tfacc-server-host-attachment1"
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:          func() { preCheck(t) },
+			ProviderFactories: providerFactories,
+			CheckDestroy:      testPritunlServerDestroy,
+			Steps: []resource.TestStep{
+				{
+					Config: testPritunlServerHostAttachmentConfig(serverName),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr("pritunl_server.test", "host_ids.#", "0"),
+						resource.TestCheckResourceAttrPair("pritunl_server_host_attachment.test", "host_id", "data.pritunl_host.test", "id"),
+						func(s *terraform.State) error {
+							attributes := s.RootModule().Resources["pritunl_server_host_attachment.test"].Primary.Attributes
+
+							hosts, err := testClient.GetHostsByServer(attributes["server_id"])
+							if err != nil {
+								return err
+							}
+							for _, host := range hosts {
+								if host.ID == attributes["host_id"] {
+									return nil
+								}
+							}
+							return fmt.Errorf("the host %s is not attached to the server %s", attributes["host_id"], attributes["server_id"])
+						},
+					),
+				},
+				// import test
+				importStep("pritunl_server_host_attachment.test"),
+			},
+		})
+	})
+
+	t.Run("rejects host_ids on a server without managed attachments", func(t *testing.T) {
+		resource.Test(t, resource.TestCase{
+			PreCheck:          func() { preCheck(t) },
+			ProviderFactories: providerFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: `
+						data "pritunl_host" "test" {
+							hostname = "pritunl.local"
+						}
+
+						resource "pritunl_server" "test" {
+							name               = "tfacc-server-host-attachment2"
+							manage_attachments = false
+							host_ids           = [data.pritunl_host.test.id]
+						}
+					`,
+					ExpectError: regexp.MustCompile("host_ids can't be set when manage_attachments is false"),
+				},
+			},
+		})
+	})
+
+	t.Run("rejects creating an online server without managed attachments", func(t *testing.T) {
+		resource.Test(t, resource.TestCase{
+			PreCheck:          func() { preCheck(t) },
+			ProviderFactories: providerFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: `
+						resource "pritunl_server" "test" {
+							name               = "tfacc-server-host-attachment3"
+							manage_attachments = false
+							status             = "online"
+						}
+					`,
+					ExpectError: regexp.MustCompile("the server can't be created online when manage_attachments is false"),
+				},
+			},
+		})
+	})
+}
+
+func testPritunlServerHostAttachmentConfig(serverName string) string {
+	return fmt.Sprintf(`
+		data "pritunl_host" "test" {
+			hostname = "pritunl.local"
+		}
+
+		resource "pritunl_server" "test" {
+			name               = "%[1]s"
+			manage_attachments = false
+		}
+
+		resource "pritunl_server_host_attachment" "test" {
+			server_id = pritunl_server.test.id
+			host_id   = data.pritunl_host.test.id
+		}
+	`, serverName)
+}
+
+// lastHostClient serves an online server with a single host and records the server starts and stops
+type lastHostClient struct {
+	pritunl.Client
+	calls *[]string
+}
+
+func (c lastHostClient) WithContext(ctx context.Context) pritunl.Client {
+	return c
+}
+
+func (c lastHostClient) GetHostsByServer(serverId string) ([]pritunl.Host, error) {
+	return []pritunl.Host{{ID: "host"}}, nil
+}
+
+func (c lastHostClient) GetServer(serverId string) (*pritunl.Server, error) {
+	return &pritunl.Server{ID: serverId, Name: "prod", Status: pritunl.ServerStatusOnline}, nil
+}
+
+func (c lastHostClient) StopServer(serverId string) error {
+	*c.calls = append(*c.calls, "stop "+serverId)
+	return nil
+}
+
+func (c lastHostClient) StartServer(serverId string) error {
+	*c.calls = append(*c.calls, "start "+serverId)
+	return nil
+}
+
+func (c lastHostClient) DetachHostFromServer(hostId, serverId string) error {
+	*c.calls = append(*c.calls, "detach "+hostId)
+	return nil
+}
+
+func TestDeleteServerHostAttachmentLastHost(t *testing.T) {
+	var calls []string
+	d := schema.TestResourceDataRaw(t, resourceServerHostAttachment().Schema, map[string]interface{}{
+		"server_id": "server",
+		"host_id":   "host",
+	})
+	d.SetId("server-host")
+
+	diags := resourceDeleteServerHostAttachment(context.Background(), d, lastHostClient{calls: &calls})
+	if len(diags) != 1 || diags[0].Severity != diag.Warning || diags[0].Summary != "Server prod was left offline" {
+		t.Errorf("expected the offline warning, got %v", diags)
+	}
+	if expected := []string{"stop server", "detach host"}; !reflect.DeepEqual(calls, expected) {
+		t.Errorf("expected the server to be left stopped, got %q", calls)
+	}
+}
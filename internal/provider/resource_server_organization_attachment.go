@@ -0,0 +1,130 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceServerOrganizationAttachment() *schema.Resource {
+	return &schema.Resource{
+		Description: "The server organization attachment resource attaches an organization to a Pritunl server. An online server is restarted to apply the change. Use it with manage_attachments = false on the pritunl_server resource.",
+		Schema: map[string]*schema.Schema{
+			"server_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the server",
+			},
+			"organization_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the organization to attach",
+			},
+		},
+		CreateContext: resourceCreateServerOrganizationAttachment,
+		ReadContext:   resourceReadServerOrganizationAttachment,
+		DeleteContext: resourceDeleteServerOrganizationAttachment,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceServerOrganizationAttachmentImport,
+		},
+	}
+}
+
+func resourceReadServerOrganizationAttachment(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	resourceMutex.RLock()
+	defer resourceMutex.RUnlock()
+
+	apiClient := requestClient(ctx, meta)
+
+	organizations, err := apiClient.GetOrganizationsByServer(d.Get("server_id").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	for _, organization := range organizations {
+		if organization.ID == d.Get("organization_id").(string) {
+			return nil
+		}
+	}
+
+	// the organization was detached outside of Terraform
+	d.SetId("")
+
+	return nil
+}
+
+func resourceCreateServerOrganizationAttachment(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	resourceMutex.Lock()
+	defer resourceMutex.Unlock()
+
+	apiClient := requestClient(ctx, meta)
+
+	serverId := d.Get("server_id").(string)
+	organizationId := d.Get("organization_id").(string)
+
+	diags := withServerStopped(apiClient, serverId, func() error {
+		err := apiClient.AttachOrganizationToServer(organizationId, serverId)
+		if err != nil {
+			return fmt.Errorf("Error on attaching server to the organization: %s", err)
+		}
+		return nil
+	})
+	if diags.HasError() {
+		return diags
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", serverId, organizationId))
+
+	return diags
+}
+
+func resourceDeleteServerOrganizationAttachment(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	resourceMutex.Lock()
+	defer resourceMutex.Unlock()
+
+	apiClient := requestClient(ctx, meta)
+
+	server, err := apiClient.GetServer(d.Get("server_id").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	diags := detachOrganizationFromServer(apiClient, d.Get("organization_id").(string), *server)
+	if diags.HasError() {
+		return diags
+	}
+
+	d.SetId("")
+
+	return diags
+}
+
+// Imports by server:${name}/org:${name} or ${serverId}/${organizationId}
+func resourceServerOrganizationAttachmentImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	apiClient := requestClient(ctx, meta)
+
+	values, err := parseImportId(d.Id(), "server", "org")
+	if err != nil {
+		return nil, err
+	}
+
+	server, err := resolveServer(apiClient, values[0])
+	if err != nil {
+		return nil, err
+	}
+
+	organization, err := resolveOrganization(apiClient, values[1])
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", server.ID, organization.ID))
+	d.Set("server_id", server.ID)
+	d.Set("organization_id", organization.ID)
+
+	return []*schema.ResourceData{d}, nil
+}
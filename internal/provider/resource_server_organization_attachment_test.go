@@ -0,0 +1,115 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccPritunlServerOrganizationAttachment(t *testing.T) {
+
+	t.Run("attaches organizations to a server", func(t *testing.T) {
+		serverName := "tfacc-server-attachment1"
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:          func() { preCheck(t) },
+			ProviderFactories: providerFactories,
+			CheckDestroy:      testPritunlServerDestroy,
+			Steps: []resource.TestStep{
+				{
+					Config: testPritunlServerOrganizationAttachmentConfig(serverName, "tfacc-org-attachment1", false),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr("pritunl_server.test", "manage_attachments", "false"),
+						resource.TestCheckResourceAttr("pritunl_server.test", "organization_ids.#", "0"),
+						testPritunlServerOrganizationAttached("pritunl_server_organization_attachment.test"),
+					),
+				},
+				{
+					Config: testPritunlServerOrganizationAttachmentConfig(serverName, "tfacc-org-attachment1", true),
+					Check: resource.ComposeTestCheckFunc(
+						testPritunlServerOrganizationAttached("pritunl_server_organization_attachment.test"),
+						testPritunlServerOrganizationAttached("pritunl_server_organization_attachment.test2"),
+					),
+				},
+				// import test
+				importStep("pritunl_server_organization_attachment.test"),
+			},
+		})
+	})
+
+	t.Run("rejects organization_ids on a server without managed attachments", func(t *testing.T) {
+		resource.Test(t, resource.TestCase{
+			PreCheck:          func() { preCheck(t) },
+			ProviderFactories: providerFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: `
+						resource "pritunl_organization" "test" {
+							name = "tfacc-org-attachment2"
+						}
+
+						resource "pritunl_server" "test" {
+							name               = "tfacc-server-attachment2"
+							manage_attachments = false
+							organization_ids   = [pritunl_organization.test.id]
+						}
+					`,
+					ExpectError: regexp.MustCompile("organization_ids can't be set when manage_attachments is false"),
+				},
+			},
+		})
+	})
+}
+
+func testPritunlServerOrganizationAttached(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		attributes := s.RootModule().Resources[name].Primary.Attributes
+
+		organizations, err := testClient.GetOrganizationsByServer(attributes["server_id"])
+		if err != nil {
+			return err
+		}
+		for _, organization := range organizations {
+			if organization.ID == attributes["organization_id"] {
+				return nil
+			}
+		}
+		return fmt.Errorf("the organization %s is not attached to the server %s", attributes["organization_id"], attributes["server_id"])
+	}
+}
+
+func testPritunlServerOrganizationAttachmentConfig(serverName, organizationName string, secondOrganization bool) string {
+	config := fmt.Sprintf(`
+		resource "pritunl_organization" "test" {
+			name = "%[2]s"
+		}
+
+		resource "pritunl_server" "test" {
+			name               = "%[1]s"
+			manage_attachments = false
+		}
+
+		resource "pritunl_server_organization_attachment" "test" {
+			server_id       = pritunl_server.test.id
+			organization_id = pritunl_organization.test.id
+		}
+	`, serverName, organizationName)
+
+	if secondOrganization {
+		config += fmt.Sprintf(`
+		resource "pritunl_organization" "test2" {
+			name = "%[1]s-2"
+		}
+
+		resource "pritunl_server_organization_attachment" "test2" {
+			server_id       = pritunl_server.test.id
+			organization_id = pritunl_organization.test2.id
+		}
+	`, organizationName)
+	}
+
+	return config
+}
@@ -28,6 +28,7 @@ func TestAccPritunlServer(t *testing.T) {
 				},
 				// import test
 				importStep("pritunl_server.test"),
+				namedImportStep("pritunl_server.test", "server:"+serverName),
 			},
 		})
 	})
@@ -360,6 +361,72 @@ func TestAccPritunlServer(t *testing.T) {
 		})
 	})
 
+	t.Run("creates a server with jumbo_frames and ipv6_firewall attributes", func(t *testing.T) {
+		serverName := "tfacc-server1"
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:          func() { preCheck(t) },
+			ProviderFactories: providerFactories,
+			CheckDestroy:      testPritunlServerDestroy,
+			Steps: []resource.TestStep{
+				{
+					Config: testPritunlServerConfigWithJumboFrames(serverName, true, false),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr("pritunl_server.test", "jumbo_frames", "true"),
+						resource.TestCheckResourceAttr("pritunl_server.test", "ipv6_firewall", "false"),
+						resource.TestCheckResourceAttr("pritunl_server.test", "wg", "false"),
+					),
+				},
+				{
+					Config: testPritunlServerConfigWithJumboFrames(serverName, false, true),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr("pritunl_server.test", "jumbo_frames", "false"),
+						resource.TestCheckResourceAttr("pritunl_server.test", "ipv6_firewall", "true"),
+					),
+				},
+				// import test
+				importStep("pritunl_server.test"),
+			},
+		})
+	})
+
+	t.Run("creates a server with error due to a conflicting network", func(t *testing.T) {
+		serverName := "tfacc-server1"
+		network := "172.16.70.0/24"
+		overlappingNetwork := "172.16.70.128/25"
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:          func() { preCheck(t) },
+			ProviderFactories: providerFactories,
+			CheckDestroy:      testPritunlServerDestroy,
+			Steps: []resource.TestStep{
+				{
+					Config: testGetServerConfigWithNetworkAndPort(serverName, network, 11111),
+				},
+				{
+					Config:      testPritunlServerConfigWithConflictingNetwork(serverName, network, overlappingNetwork),
+					ExpectError: regexp.MustCompile(fmt.Sprintf("network %s overlaps with network %s of the server %s", overlappingNetwork, network, serverName)),
+				},
+			},
+		})
+	})
+
+	t.Run("creates a server with error due to network_start outside of the network", func(t *testing.T) {
+		serverName := "tfacc-server1"
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:          func() { preCheck(t) },
+			ProviderFactories: providerFactories,
+			CheckDestroy:      testPritunlServerDestroy,
+			Steps: []resource.TestStep{
+				{
+					Config:      testPritunlServerConfigWithBridgeNetwork(serverName, "172.16.71.0/24", "172.16.72.10", "172.16.71.100"),
+					ExpectError: regexp.MustCompile("network_start 172.16.72.10 is outside of the server network 172.16.71.0/24"),
+				},
+			},
+		})
+	})
+
 	t.Run("creates a server with groups attribute", func(t *testing.T) {
 		serverName := "tfacc-server1"
 
@@ -501,6 +568,46 @@ func testGetServerConfigWithBindAddress(name, network, bindAddress string, port
 	`, name, network, bindAddress, port)
 }
 
+func testPritunlServerConfigWithJumboFrames(name string, jumboFrames, ipv6Firewall bool) string {
+	return fmt.Sprintf(`
+		resource "pritunl_server" "test" {
+			name          = "%[1]s"
+			jumbo_frames  = %[2]v
+			ipv6_firewall = %[3]v
+		}
+	`, name, jumboFrames, ipv6Firewall)
+}
+
+func testPritunlServerConfigWithConflictingNetwork(name, network, conflictingNetwork string) string {
+	return fmt.Sprintf(`
+		resource "pritunl_server" "test" {
+			name     = "%[1]s"
+			network  = "%[2]s"
+			port     = 11111
+			protocol = "tcp"
+		}
+
+		resource "pritunl_server" "conflict" {
+			name     = "%[1]s-conflict"
+			network  = "%[3]s"
+			port     = 11112
+			protocol = "tcp"
+		}
+	`, name, network, conflictingNetwork)
+}
+
+func testPritunlServerConfigWithBridgeNetwork(name, network, networkStart, networkEnd string) string {
+	return fmt.Sprintf(`
+		resource "pritunl_server" "test" {
+			name          = "%[1]s"
+			network       = "%[2]s"
+			network_mode  = "bridge"
+			network_start = "%[3]s"
+			network_end   = "%[4]s"
+		}
+	`, name, network, networkStart, networkEnd)
+}
+
 func testPritunlServerConfigWithGroups(name string, groupName string) string {
 	return fmt.Sprintf(`
 		resource "pritunl_server" "test" {
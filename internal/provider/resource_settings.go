@@ -0,0 +1,246 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/maulid7/terraform-provider-pritunl/pritunl"
+)
+
+// Pritunl has a single settings document, so every pritunl_settings resource has the same ID
+const settingsId = "settings"
+
+func resourceSettings() *schema.Resource {
+	return &schema.Resource{
+		Description: "The settings resource allows managing global Pritunl settings. Only the attributes set in the configuration are managed, the other settings are left untouched. Destroying the resource keeps the settings as they are.",
+		Schema: map[string]*schema.Schema{
+			"theme": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Description:  "Theme of the web console",
+				ValidateFunc: validation.StringInSlice([]string{"light", "dark"}, false),
+			},
+			"public_address": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Public IP address or domain name of the Pritunl cluster",
+			},
+			"public_address6": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Public IPv6 address or domain name of the Pritunl cluster",
+			},
+			"routed_subnet6": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Description:  "Default IPv6 subnet routed to the hosts",
+				ValidateFunc: validation.Any(validation.StringIsEmpty, validation.IsCIDR),
+			},
+			"routed_subnet6_wg": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Description:  "Default IPv6 WG subnet routed to the hosts",
+				ValidateFunc: validation.Any(validation.StringIsEmpty, validation.IsCIDR),
+			},
+			"reverse_proxy": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "Trust the X-Forwarded-For header of a reverse proxy in front of the web server",
+			},
+			"server_port": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				Description:  "Port of the web server",
+				ValidateFunc: validation.IsPortNumber,
+			},
+			"acme_domain": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Domain to request a Let's Encrypt certificate for the web server",
+			},
+			"restrict_import": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "Allow users to import profiles only with a temporary profile link or URI",
+			},
+			"client_reconnect": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "Let clients reconnect automatically after a connection loss",
+			},
+			"pin_mode": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Description:  "Whether users have to set a PIN, one of optional, required or disabled",
+				ValidateFunc: validation.StringInSlice([]string{"optional", "required", "disabled"}, false),
+			},
+			"email_from": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Sender address of the emails sent by Pritunl",
+			},
+			"email_server": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Address of the SMTP server",
+			},
+			"email_username": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Username of the SMTP server",
+			},
+			"email_password": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "Password of the SMTP server. Pritunl doesn't return the password, so changes made outside of Terraform aren't detected",
+			},
+		},
+		CreateContext: resourceCreateSettings,
+		ReadContext:   resourceReadSettings,
+		UpdateContext: resourceUpdateSettings,
+		DeleteContext: resourceDeleteSettings,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+				d.SetId(settingsId)
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+	}
+}
+
+// Settings attributes managed by the resource, the key matches the JSON attribute name
+var settingsAttributes = []string{
+	"theme", "public_address", "public_address6", "routed_subnet6", "routed_subnet6_wg",
+	"reverse_proxy", "server_port", "acme_domain", "restrict_import", "client_reconnect",
+	"pin_mode", "email_from", "email_server", "email_username", "email_password",
+}
+
+func resourceReadSettings(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	apiClient := requestClient(ctx, meta)
+
+	settings, err := apiClient.GetSettings()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("theme", settings.Theme)
+	d.Set("public_address", settings.PublicAddress)
+	d.Set("public_address6", settings.PublicAddress6)
+	d.Set("routed_subnet6", settings.RoutedSubnet6)
+	d.Set("routed_subnet6_wg", settings.RoutedSubnet6WG)
+	d.Set("reverse_proxy", settings.ReverseProxy)
+	d.Set("server_port", settings.ServerPort)
+	d.Set("acme_domain", settings.AcmeDomain)
+	d.Set("restrict_import", settings.RestrictImport)
+	d.Set("client_reconnect", settings.ClientReconnect)
+	d.Set("pin_mode", settings.PinMode)
+	d.Set("email_from", settings.EmailFrom)
+	d.Set("email_server", settings.EmailServer)
+	d.Set("email_username", settings.EmailUsername)
+
+	return nil
+}
+
+func resourceCreateSettings(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	apiClient := requestClient(ctx, meta)
+
+	var declaredAttributes []string
+	for _, key := range settingsAttributes {
+		if _, ok := d.GetOkExists(key); ok {
+			declaredAttributes = append(declaredAttributes, key)
+		}
+	}
+
+	if len(declaredAttributes) > 0 {
+		err := apiClient.UpdateSettings(expandSettings(d, declaredAttributes))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	d.SetId(settingsId)
+
+	return resourceReadSettings(ctx, d, meta)
+}
+
+func resourceUpdateSettings(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	apiClient := requestClient(ctx, meta)
+
+	changed := changedAttributes(d, settingsAttributes...)
+	if len(changed) > 0 {
+		err := apiClient.UpdateSettings(expandSettings(d, changed))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceReadSettings(ctx, d, meta)
+}
+
+// Settings are global and can't be removed, so the resource is only released from Terraform management
+func resourceDeleteSettings(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	d.SetId("")
+
+	return nil
+}
+
+// expandSettings builds a settings update holding only the given attributes, the other settings aren't sent
+func expandSettings(d *schema.ResourceData, keys []string) *pritunl.Settings {
+	settings := &pritunl.Settings{
+		ForceSendFields: keys,
+	}
+
+	for _, key := range keys {
+		switch key {
+		case "theme":
+			settings.Theme = d.Get(key).(string)
+		case "public_address":
+			settings.PublicAddress = d.Get(key).(string)
+		case "public_address6":
+			settings.PublicAddress6 = d.Get(key).(string)
+		case "routed_subnet6":
+			settings.RoutedSubnet6 = d.Get(key).(string)
+		case "routed_subnet6_wg":
+			settings.RoutedSubnet6WG = d.Get(key).(string)
+		case "reverse_proxy":
+			settings.ReverseProxy = d.Get(key).(bool)
+		case "server_port":
+			settings.ServerPort = d.Get(key).(int)
+		case "acme_domain":
+			settings.AcmeDomain = d.Get(key).(string)
+		case "restrict_import":
+			settings.RestrictImport = d.Get(key).(bool)
+		case "client_reconnect":
+			settings.ClientReconnect = d.Get(key).(bool)
+		case "pin_mode":
+			settings.PinMode = d.Get(key).(string)
+		case "email_from":
+			settings.EmailFrom = d.Get(key).(string)
+		case "email_server":
+			settings.EmailServer = d.Get(key).(string)
+		case "email_username":
+			settings.EmailUsername = d.Get(key).(string)
+		case "email_password":
+			settings.EmailPassword = pritunl.WriteOnlyString(d.Get(key).(string))
+		}
+	}
+
+	return settings
+}
@@ -0,0 +1,48 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccPritunlSettings(t *testing.T) {
+
+	t.Run("manages declared settings only", func(t *testing.T) {
+		resource.Test(t, resource.TestCase{
+			PreCheck:          func() { preCheck(t) },
+			ProviderFactories: providerFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: testPritunlSettingsConfig(true, "tfacc@example.com"),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr("pritunl_settings.test", "id", "settings"),
+						resource.TestCheckResourceAttr("pritunl_settings.test", "restrict_import", "true"),
+						resource.TestCheckResourceAttr("pritunl_settings.test", "email_from", "tfacc@example.com"),
+						resource.TestCheckResourceAttrSet("pritunl_settings.test", "server_port"),
+					),
+				},
+				{
+					Config: testPritunlSettingsConfig(false, "tfacc2@example.com"),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr("pritunl_settings.test", "restrict_import", "false"),
+						resource.TestCheckResourceAttr("pritunl_settings.test", "email_from", "tfacc2@example.com"),
+					),
+				},
+				// import test
+				importStep("pritunl_settings.test", "email_password"),
+			},
+		})
+	})
+}
+
+func testPritunlSettingsConfig(restrictImport bool, emailFrom string) string {
+	return fmt.Sprintf(`
+		resource "pritunl_settings" "test" {
+			restrict_import = %[1]v
+			email_from      = "%[2]s"
+			email_password  = "tfacc-password"
+		}
+	`, restrictImport, emailFrom)
+}
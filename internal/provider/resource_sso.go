@@ -0,0 +1,473 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/maulid7/terraform-provider-pritunl/pritunl"
+)
+
+// Pritunl has a single SSO configuration, so every pritunl_sso resource has the same ID
+const ssoId = "sso"
+
+// Single sign-on modes, a mode combines the providers joined with "_", e.g. saml_okta_duo
+var ssoModes = []string{
+	"duo", "yubico", "azure", "azure_duo", "azure_yubico", "google", "google_duo", "google_yubico",
+	"slack", "slack_duo", "slack_yubico", "saml", "saml_duo", "saml_yubico", "saml_okta", "saml_okta_duo",
+	"saml_okta_yubico", "saml_onelogin", "saml_onelogin_duo", "saml_onelogin_yubico", "radius", "radius_duo", "plugin",
+}
+
+// Providers configured with a nested block, the other providers only need the mode
+var ssoProviderBlocks = []string{"google", "azure", "saml", "okta", "onelogin", "radius", "duo", "yubico"}
+
+// Providers that work without their block, e.g. Google only needs it to look up the user groups
+var ssoOptionalProviderBlocks = map[string]bool{"google": true}
+
+// Providers that authenticate only the users matching the match attribute
+var ssoMatchProviders = map[string]bool{"google": true, "slack": true}
+
+// SSO attributes of the settings API, all of them are sent as the resource owns the whole SSO configuration
+var ssoAttributes = []string{
+	"sso", "sso_match", "sso_org", "sso_cache", "sso_client_cache",
+	"sso_google_email", "sso_google_key",
+	"sso_azure_directory_id", "sso_azure_app_id", "sso_azure_app_secret",
+	"sso_saml_url", "sso_saml_issuer_url", "sso_saml_cert",
+	"sso_okta_app_id", "sso_okta_token", "sso_okta_mode",
+	"sso_onelogin_app_id", "sso_onelogin_id", "sso_onelogin_secret", "sso_onelogin_mode",
+	"sso_radius_host", "sso_radius_secret",
+	"sso_duo_host", "sso_duo_token", "sso_duo_secret", "sso_duo_mode",
+	"sso_yubico_client", "sso_yubico_secret",
+}
+
+func resourceSso() *schema.Resource {
+	return &schema.Resource{
+		Description: "The SSO resource allows managing the single sign-on configuration of Pritunl. Destroying the resource disables single sign-on.",
+		Schema: map[string]*schema.Schema{
+			"mode": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "Single sign-on mode, the providers of the mode are joined with an underscore, e.g. saml_okta_duo. Every provider of the mode except google requires its block",
+				ValidateFunc: validation.StringInSlice(ssoModes, false),
+			},
+			"organization_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Organization new single sign-on users are added to when the provider doesn't map them to an organization",
+			},
+			"match": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Domains (Google) or team names (Slack) users have to belong to. Required by the google and slack modes",
+			},
+			"cache": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Cache single sign-on results so that clients reconnecting from the same address don't authenticate again",
+			},
+			"client_cache": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Cache single sign-on results on the clients so that they don't authenticate again on reconnect",
+			},
+			"google": ssoProviderSchema("Google Workspace settings used to look up the user groups", map[string]*schema.Schema{
+				"email": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "Email of the admin account the service account acts on behalf of",
+				},
+				"key": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Sensitive:   true,
+					Description: "JSON key of the service account",
+				},
+			}),
+			"azure": ssoProviderSchema("Azure Active Directory settings", map[string]*schema.Schema{
+				"directory_id": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Description: "Directory (tenant) ID",
+				},
+				"app_id": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Description: "Application ID",
+				},
+				"app_secret": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Sensitive:   true,
+					Description: "Application secret",
+				},
+			}),
+			"saml": ssoProviderSchema("SAML settings, also used by the Okta and OneLogin modes", map[string]*schema.Schema{
+				"url": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Description: "SAML single sign-on URL",
+				},
+				"issuer_url": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Description: "SAML issuer URL",
+				},
+				"cert": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Description: "PEM encoded SAML certificate",
+				},
+			}),
+			"okta": ssoProviderSchema("Okta settings", map[string]*schema.Schema{
+				"app_id": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Description: "Okta application ID",
+				},
+				"token": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Sensitive:   true,
+					Description: "Okta API token",
+				},
+				"mode": ssoPushModeSchema("Okta"),
+			}),
+			"onelogin": ssoProviderSchema("OneLogin settings", map[string]*schema.Schema{
+				"app_id": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Description: "OneLogin application ID",
+				},
+				"client_id": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Description: "OneLogin API client ID",
+				},
+				"client_secret": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Sensitive:   true,
+					Description: "OneLogin API client secret",
+				},
+				"mode": ssoPushModeSchema("OneLogin"),
+			}),
+			"radius": ssoProviderSchema("Radius settings", map[string]*schema.Schema{
+				"host": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Description: "Radius server host, the port can be appended after a colon",
+				},
+				"secret": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Sensitive:   true,
+					Description: "Radius shared secret",
+				},
+			}),
+			"duo": ssoProviderSchema("Duo settings", map[string]*schema.Schema{
+				"host": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Description: "Duo API hostname",
+				},
+				"token": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Description: "Duo integration key",
+				},
+				"secret": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Sensitive:   true,
+					Description: "Duo secret key",
+				},
+				"mode": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "push",
+					Description:  "Duo authentication mode, one of push, phone or passcode",
+					ValidateFunc: validation.StringInSlice([]string{"push", "phone", "passcode"}, false),
+				},
+			}),
+			"yubico": ssoProviderSchema("YubiKey settings", map[string]*schema.Schema{
+				"client_id": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Description: "Yubico client ID",
+				},
+				"secret": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Sensitive:   true,
+					Description: "Yubico secret key",
+				},
+			}),
+		},
+		CreateContext: resourceUpdateSso,
+		ReadContext:   resourceReadSso,
+		UpdateContext: resourceUpdateSso,
+		DeleteContext: resourceDeleteSso,
+		CustomizeDiff: resourceSsoCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+				d.SetId(ssoId)
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+	}
+}
+
+func ssoProviderSchema(description string, attributes map[string]*schema.Schema) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Description: description,
+		Elem: &schema.Resource{
+			Schema: attributes,
+		},
+	}
+}
+
+func ssoPushModeSchema(provider string) *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		Default:      "push",
+		Description:  fmt.Sprintf("%s authentication mode, one of push, passcode or none", provider),
+		ValidateFunc: validation.StringInSlice([]string{"push", "passcode", "none"}, false),
+	}
+}
+
+func resourceSsoCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("mode") {
+		return nil
+	}
+
+	declaredBlocks := make(map[string]bool)
+	for _, provider := range ssoProviderBlocks {
+		declaredBlocks[provider] = len(d.Get(provider).([]interface{})) > 0
+	}
+
+	hasMatch := !d.NewValueKnown("match") || len(d.Get("match").([]interface{})) > 0
+
+	return validateSsoMode(d.Get("mode").(string), declaredBlocks, hasMatch)
+}
+
+// validateSsoMode checks that every provider of the mode has its settings and that no unused provider is configured
+func validateSsoMode(mode string, declaredBlocks map[string]bool, hasMatch bool) error {
+	modeProviders := make(map[string]bool)
+	for _, provider := range strings.Split(mode, "_") {
+		modeProviders[provider] = true
+	}
+
+	problems := make([]string, 0)
+
+	for _, provider := range ssoProviderBlocks {
+		if modeProviders[provider] && !declaredBlocks[provider] && !ssoOptionalProviderBlocks[provider] {
+			problems = append(problems, fmt.Sprintf("the %s block is required by the %s mode", provider, mode))
+		}
+		if !modeProviders[provider] && declaredBlocks[provider] {
+			problems = append(problems, fmt.Sprintf("the %s block is not used by the %s mode", provider, mode))
+		}
+	}
+
+	for provider := range ssoMatchProviders {
+		if modeProviders[provider] && !hasMatch {
+			problems = append(problems, fmt.Sprintf("the match attribute is required by the %s mode", mode))
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	sort.Strings(problems)
+
+	return fmt.Errorf("invalid single sign-on configuration:\n%s", strings.Join(problems, "\n"))
+}
+
+func resourceReadSso(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	apiClient := requestClient(ctx, meta)
+
+	settings, err := apiClient.GetSettings()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if settings.Sso == "" {
+		// single sign-on was disabled outside of Terraform
+		d.SetId("")
+		return nil
+	}
+
+	modeProviders := make(map[string]bool)
+	for _, provider := range strings.Split(settings.Sso, "_") {
+		modeProviders[provider] = true
+	}
+
+	providerBlock := func(provider string, attributes map[string]interface{}) []interface{} {
+		if !modeProviders[provider] {
+			return nil
+		}
+		return []interface{}{attributes}
+	}
+
+	d.Set("mode", settings.Sso)
+	d.Set("organization_id", settings.SsoOrg)
+	d.Set("match", settings.SsoMatch)
+	d.Set("cache", settings.SsoCache)
+	d.Set("client_cache", settings.SsoClientCache)
+
+	google := providerBlock("google", map[string]interface{}{
+		"email": settings.SsoGoogleEmail,
+		"key":   settings.SsoGoogleKey,
+	})
+	if settings.SsoGoogleEmail == "" && settings.SsoGoogleKey == "" {
+		google = nil
+	}
+	d.Set("google", google)
+
+	d.Set("azure", providerBlock("azure", map[string]interface{}{
+		"directory_id": settings.SsoAzureDirectoryId,
+		"app_id":       settings.SsoAzureAppId,
+		"app_secret":   settings.SsoAzureAppSecret,
+	}))
+	d.Set("saml", providerBlock("saml", map[string]interface{}{
+		"url":        settings.SsoSamlUrl,
+		"issuer_url": settings.SsoSamlIssuerUrl,
+		"cert":       settings.SsoSamlCert,
+	}))
+	d.Set("okta", providerBlock("okta", map[string]interface{}{
+		"app_id": settings.SsoOktaAppId,
+		"token":  settings.SsoOktaToken,
+		"mode":   settings.SsoOktaMode,
+	}))
+	d.Set("onelogin", providerBlock("onelogin", map[string]interface{}{
+		"app_id":        settings.SsoOneloginAppId,
+		"client_id":     settings.SsoOneloginId,
+		"client_secret": settings.SsoOneloginSecret,
+		"mode":          settings.SsoOneloginMode,
+	}))
+	d.Set("radius", providerBlock("radius", map[string]interface{}{
+		"host":   settings.SsoRadiusHost,
+		"secret": settings.SsoRadiusSecret,
+	}))
+	d.Set("duo", providerBlock("duo", map[string]interface{}{
+		"host":   settings.SsoDuoHost,
+		"token":  settings.SsoDuoToken,
+		"secret": settings.SsoDuoSecret,
+		"mode":   settings.SsoDuoMode,
+	}))
+	d.Set("yubico", providerBlock("yubico", map[string]interface{}{
+		"client_id": settings.SsoYubicoClient,
+		"secret":    settings.SsoYubicoSecret,
+	}))
+
+	return nil
+}
+
+// Creating and updating both replace the whole SSO configuration
+func resourceUpdateSso(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	apiClient := requestClient(ctx, meta)
+
+	err := apiClient.UpdateSettings(expandSsoSettings(d))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(ssoId)
+
+	return resourceReadSso(ctx, d, meta)
+}
+
+func resourceDeleteSso(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	apiClient := requestClient(ctx, meta)
+
+	err := apiClient.UpdateSettings(&pritunl.Settings{
+		ForceSendFields: []string{"sso"},
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+func expandSsoSettings(d *schema.ResourceData) *pritunl.Settings {
+	settings := &pritunl.Settings{
+		Sso:             d.Get("mode").(string),
+		SsoMatch:        expandStringList(d.Get("match")),
+		SsoOrg:          d.Get("organization_id").(string),
+		SsoCache:        d.Get("cache").(bool),
+		SsoClientCache:  d.Get("client_cache").(bool),
+		ForceSendFields: ssoAttributes,
+	}
+
+	if google := ssoProviderAttributes(d, "google"); google != nil {
+		settings.SsoGoogleEmail = google["email"].(string)
+		settings.SsoGoogleKey = google["key"].(string)
+	}
+
+	if azure := ssoProviderAttributes(d, "azure"); azure != nil {
+		settings.SsoAzureDirectoryId = azure["directory_id"].(string)
+		settings.SsoAzureAppId = azure["app_id"].(string)
+		settings.SsoAzureAppSecret = azure["app_secret"].(string)
+	}
+
+	if saml := ssoProviderAttributes(d, "saml"); saml != nil {
+		settings.SsoSamlUrl = saml["url"].(string)
+		settings.SsoSamlIssuerUrl = saml["issuer_url"].(string)
+		settings.SsoSamlCert = saml["cert"].(string)
+	}
+
+	if okta := ssoProviderAttributes(d, "okta"); okta != nil {
+		settings.SsoOktaAppId = okta["app_id"].(string)
+		settings.SsoOktaToken = okta["token"].(string)
+		settings.SsoOktaMode = okta["mode"].(string)
+	}
+
+	if onelogin := ssoProviderAttributes(d, "onelogin"); onelogin != nil {
+		settings.SsoOneloginAppId = onelogin["app_id"].(string)
+		settings.SsoOneloginId = onelogin["client_id"].(string)
+		settings.SsoOneloginSecret = onelogin["client_secret"].(string)
+		settings.SsoOneloginMode = onelogin["mode"].(string)
+	}
+
+	if radius := ssoProviderAttributes(d, "radius"); radius != nil {
+		settings.SsoRadiusHost = radius["host"].(string)
+		settings.SsoRadiusSecret = radius["secret"].(string)
+	}
+
+	if duo := ssoProviderAttributes(d, "duo"); duo != nil {
+		settings.SsoDuoHost = duo["host"].(string)
+		settings.SsoDuoToken = duo["token"].(string)
+		settings.SsoDuoSecret = duo["secret"].(string)
+		settings.SsoDuoMode = duo["mode"].(string)
+	}
+
+	if yubico := ssoProviderAttributes(d, "yubico"); yubico != nil {
+		settings.SsoYubicoClient = yubico["client_id"].(string)
+		settings.SsoYubicoSecret = yubico["secret"].(string)
+	}
+
+	return settings
+}
+
+func ssoProviderAttributes(d *schema.ResourceData, provider string) map[string]interface{} {
+	blocks := d.Get(provider).([]interface{})
+	if len(blocks) == 0 || blocks[0] == nil {
+		return nil
+	}
+
+	return blocks[0].(map[string]interface{})
+}
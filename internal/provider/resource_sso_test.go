@@ -0,0 +1,133 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccPritunlSso(t *testing.T) {
+
+	t.Run("configures single sign-on providers", func(t *testing.T) {
+		resource.Test(t, resource.TestCase{
+			PreCheck:          func() { preCheck(t) },
+			ProviderFactories: providerFactories,
+			CheckDestroy:      testPritunlSsoDestroy,
+			Steps: []resource.TestStep{
+				{
+					Config: testPritunlSsoRadiusConfig("radius.example.com"),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr("pritunl_sso.test", "mode", "radius"),
+						resource.TestCheckResourceAttr("pritunl_sso.test", "radius.0.host", "radius.example.com"),
+						resource.TestCheckResourceAttr("pritunl_sso.test", "cache", "true"),
+					),
+				},
+				{
+					Config: testPritunlSsoSamlConfig("https://sso.example.com/saml"),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr("pritunl_sso.test", "mode", "saml"),
+						resource.TestCheckResourceAttr("pritunl_sso.test", "saml.0.url", "https://sso.example.com/saml"),
+						resource.TestCheckResourceAttr("pritunl_sso.test", "radius.#", "0"),
+					),
+				},
+				// import test
+				importStep("pritunl_sso.test"),
+			},
+		})
+	})
+
+	t.Run("fails without the blocks required by the mode", func(t *testing.T) {
+		resource.Test(t, resource.TestCase{
+			PreCheck:          func() { preCheck(t) },
+			ProviderFactories: providerFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: `
+						resource "pritunl_sso" "test" {
+							mode = "saml_okta"
+						}
+					`,
+					ExpectError: regexp.MustCompile("the okta block is required by the saml_okta mode"),
+				},
+			},
+		})
+	})
+}
+
+func TestValidateSsoMode(t *testing.T) {
+	testCases := []struct {
+		name           string
+		mode           string
+		declaredBlocks map[string]bool
+		hasMatch       bool
+		expectedErr    string
+	}{
+		{"google with match", "google", map[string]bool{}, true, ""},
+		{"google without match", "google", map[string]bool{}, false, "the match attribute is required by the google mode"},
+		{"google with group lookup", "google_duo", map[string]bool{"google": true, "duo": true}, true, ""},
+		{"saml okta duo", "saml_okta_duo", map[string]bool{"saml": true, "okta": true, "duo": true}, false, ""},
+		{"missing duo", "radius_duo", map[string]bool{"radius": true}, false, "the duo block is required by the radius_duo mode"},
+		{"unused block", "azure", map[string]bool{"azure": true, "radius": true}, false, "the radius block is not used by the azure mode"},
+		{"plugin", "plugin", map[string]bool{}, false, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateSsoMode(tc.mode, tc.declaredBlocks, tc.hasMatch)
+			if tc.expectedErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %s", err)
+				}
+				return
+			}
+
+			if err == nil || !regexp.MustCompile(tc.expectedErr).MatchString(err.Error()) {
+				t.Errorf("expected an error matching %q, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func testPritunlSsoDestroy(s *terraform.State) error {
+	settings, err := testClient.GetSettings()
+	if err != nil {
+		return err
+	}
+
+	if settings.Sso != "" {
+		return fmt.Errorf("single sign-on is still enabled with the %s mode", settings.Sso)
+	}
+
+	return nil
+}
+
+func testPritunlSsoRadiusConfig(host string) string {
+	return fmt.Sprintf(`
+		resource "pritunl_sso" "test" {
+			mode  = "radius"
+			cache = true
+
+			radius {
+				host   = "%[1]s"
+				secret = "tfacc-secret"
+			}
+		}
+	`, host)
+}
+
+func testPritunlSsoSamlConfig(url string) string {
+	return fmt.Sprintf(`
+		resource "pritunl_sso" "test" {
+			mode = "saml"
+
+			saml {
+				url        = "%[1]s"
+				issuer_url = "https://sso.example.com/issuer"
+				cert       = "tfacc-cert"
+			}
+		}
+	`, url)
+}
@@ -3,9 +3,8 @@ package provider
 import (
 	"context"
 	"fmt"
-	"strings"
 
-	"github.com/maulid7/terraform-provider-pritunl/internal/pritunl"
+	"github.com/maulid7/terraform-provider-pritunl/pritunl"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -118,8 +117,8 @@ func resourceUser() *schema.Resource {
 	}
 }
 
-func resourceUserRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	apiClient := meta.(pritunl.Client)
+func resourceUserRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	apiClient := requestClient(ctx, meta)
 
 	user, err := apiClient.GetUser(d.Id(), d.Get("organization_id").(string))
 	if err != nil {
@@ -156,8 +155,8 @@ func resourceUserRead(_ context.Context, d *schema.ResourceData, meta interface{
 	return nil
 }
 
-func resourceUserDelete(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	apiClient := meta.(pritunl.Client)
+func resourceUserDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	apiClient := requestClient(ctx, meta)
 
 	err := apiClient.DeleteUser(d.Id(), d.Get("organization_id").(string))
 	if err != nil {
@@ -170,17 +169,16 @@ func resourceUserDelete(_ context.Context, d *schema.ResourceData, meta interfac
 }
 
 func resourceUserUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	apiClient := meta.(pritunl.Client)
+	apiClient := requestClient(ctx, meta)
 
 	user, err := apiClient.GetUser(d.Id(), d.Get("organization_id").(string))
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
+	// an empty secret clears the PIN
 	if d.HasChange("pin") {
-		if v, ok := d.GetOk("pin"); ok {
-			user.Pin = &pritunl.Pin{Secret: v.(string)}
-		}
+		user.Pin = &pritunl.Pin{Secret: d.Get("pin").(string)}
 	}
 
 	if v, ok := d.GetOk("name"); ok {
@@ -199,13 +197,12 @@ func resourceUserUpdate(ctx context.Context, d *schema.ResourceData, meta interf
 		user.Groups = groups
 	}
 
-	if v, ok := d.GetOk("email"); ok {
-		user.Email = v.(string)
+	if d.HasChange("email") {
+		user.Email = d.Get("email").(string)
 	}
 
-	// TODO: Fixme
-	if v, ok := d.GetOk("disabled"); ok {
-		user.Disabled = v.(bool)
+	if d.HasChange("disabled") {
+		user.Disabled = d.Get("disabled").(bool)
 	}
 
 	if d.HasChange("port_forwarding") {
@@ -224,12 +221,12 @@ func resourceUserUpdate(ctx context.Context, d *schema.ResourceData, meta interf
 		user.NetworkLinks = networkLinks
 	}
 
-	if v, ok := d.GetOk("client_to_client"); ok {
-		user.ClientToClient = v.(bool)
+	if d.HasChange("client_to_client") {
+		user.ClientToClient = d.Get("client_to_client").(bool)
 	}
 
-	if v, ok := d.GetOk("auth_type"); ok {
-		user.AuthType = v.(string)
+	if d.HasChange("auth_type") {
+		user.AuthType = d.Get("auth_type").(string)
 	}
 
 	if d.HasChange("mac_addresses") {
@@ -248,14 +245,17 @@ func resourceUserUpdate(ctx context.Context, d *schema.ResourceData, meta interf
 		user.DnsServers = dnsServers
 	}
 
-	if v, ok := d.GetOk("dns_suffix"); ok {
-		user.DnsSuffix = v.(string)
+	if d.HasChange("dns_suffix") {
+		user.DnsSuffix = d.Get("dns_suffix").(string)
 	}
 
-	if v, ok := d.GetOk("bypass_secondary"); ok {
-		user.BypassSecondary = v.(bool)
+	if d.HasChange("bypass_secondary") {
+		user.BypassSecondary = d.Get("bypass_secondary").(bool)
 	}
 
+	// changed attributes have to reach the API even when they are cleared
+	user.ForceSendFields = changedAttributes(d, userAttributes...)
+
 	err = apiClient.UpdateUser(d.Id(), user)
 	if err != nil {
 		return diag.FromErr(err)
@@ -264,8 +264,14 @@ func resourceUserUpdate(ctx context.Context, d *schema.ResourceData, meta interf
 	return resourceUserRead(ctx, d, meta)
 }
 
-func resourceUserCreate(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	apiClient := meta.(pritunl.Client)
+// User attributes sent to the Pritunl API as is, the key matches the JSON attribute name
+var userAttributes = []string{
+	"name", "groups", "email", "disabled", "port_forwarding", "network_links", "client_to_client",
+	"auth_type", "mac_addresses", "dns_servers", "dns_suffix", "bypass_secondary", "pin",
+}
+
+func resourceUserCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	apiClient := requestClient(ctx, meta)
 
 	dnsServers := make([]string, 0)
 	for _, v := range d.Get("dns_servers").([]interface{}) {
@@ -324,24 +330,27 @@ func resourceUserCreate(_ context.Context, d *schema.ResourceData, meta interfac
 	return nil
 }
 
-func resourceUserImport(_ context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-	apiClient := meta.(pritunl.Client)
+// Imports by org:${name}/user:${name} or ${organizationId}/${userId}, the legacy ${organizationId}-${userId} format is supported too
+func resourceUserImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	apiClient := requestClient(ctx, meta)
 
-	attributes := strings.Split(d.Id(), "-")
-	if len(attributes) < 2 {
-		return nil, fmt.Errorf("invalid format: expected ${organizationId}-${userId}, e.g. 60cd0be07723cf3c9114686c-60cd0be17723cf3c91146873, actual id is %s", d.Id())
+	values, err := parseImportId(d.Id(), "org", "user")
+	if err != nil {
+		return nil, err
 	}
 
-	orgId := attributes[0]
-	userId := attributes[1]
-
-	d.SetId(userId)
-	d.Set("organization_id", orgId)
+	organization, err := resolveOrganization(apiClient, values[0])
+	if err != nil {
+		return nil, fmt.Errorf("error on getting organization during import: %s", err)
+	}
 
-	_, err := apiClient.GetUser(userId, orgId)
+	user, err := resolveUser(apiClient, organization.ID, values[1])
 	if err != nil {
 		return nil, fmt.Errorf("error on getting user during import: %s", err)
 	}
 
+	d.SetId(user.ID)
+	d.Set("organization_id", organization.ID)
+
 	return []*schema.ResourceData{d}, nil
 }
@@ -28,6 +28,7 @@ func TestAccPritunlUser(t *testing.T) {
 				},
 				// import test
 				pritunlUserImportStep("pritunl_user.test"),
+				namedImportStep("pritunl_user.test", fmt.Sprintf("org:%s/user:%s", orgName, username), "pin"),
 			},
 		})
 	})
@@ -55,6 +56,31 @@ func TestAccPritunlUser(t *testing.T) {
 			},
 		})
 	})
+	t.Run("clears user attributes on update", func(t *testing.T) {
+		username := "tfacc-user3"
+		orgName := "tfacc-org3"
+
+		resource.Test(t, resource.TestCase{
+			PreCheck:          func() { preCheck(t) },
+			ProviderFactories: providerFactories,
+			Steps: []resource.TestStep{
+				{
+					Config: testPritunlUserConfigWithDisabled(username, orgName, true, "tfacc@example.com"),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr("pritunl_user.test", "disabled", "true"),
+						resource.TestCheckResourceAttr("pritunl_user.test", "email", "tfacc@example.com"),
+					),
+				},
+				{
+					Config: testPritunlUserConfigWithDisabled(username, orgName, false, ""),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr("pritunl_user.test", "disabled", "false"),
+						resource.TestCheckResourceAttr("pritunl_user.test", "email", ""),
+					),
+				},
+			},
+		})
+	})
 }
 
 func testPritunlUserConfig(username, orgName string) string {
@@ -80,3 +106,18 @@ resource "pritunl_user" "test" {
 
 	return resources
 }
+
+func testPritunlUserConfigWithDisabled(username, orgName string, disabled bool, email string) string {
+	return fmt.Sprintf(`
+resource "pritunl_organization" "test" {
+    name = "%[2]s"
+}
+
+resource "pritunl_user" "test" {
+    name            = "%[1]s"
+    organization_id = pritunl_organization.test.id
+    disabled        = %[3]t
+    email           = "%[4]s"
+}
+`, username, orgName, disabled, email)
+}
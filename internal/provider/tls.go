@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// tlsSettings holds the TLS attributes of the provider
+type tlsSettings struct {
+	caCertPem     string
+	caCertFile    string
+	clientCertPem string
+	clientKeyPem  string
+	serverName    string
+	minVersion    string
+}
+
+// withEnvironment reads the ca_cert_file from PRITUNL_CA_CERT_FILE when neither ca_cert_pem nor
+// ca_cert_file is configured, so the variable can't conflict with a configured CA
+func (s tlsSettings) withEnvironment(getenv func(string) string) tlsSettings {
+	if s.caCertPem == "" && s.caCertFile == "" {
+		s.caCertFile = getenv("PRITUNL_CA_CERT_FILE")
+	}
+
+	return s
+}
+
+// config returns the TLS configuration of the API client. A custom CA replaces the system ones.
+func (s tlsSettings) config() (*tls.Config, error) {
+	config := &tls.Config{ServerName: s.serverName}
+
+	if s.minVersion != "" {
+		version, ok := tlsVersions[s.minVersion]
+		if !ok {
+			return nil, fmt.Errorf("unsupported min_tls_version %q", s.minVersion)
+		}
+		config.MinVersion = version
+	}
+
+	caCertPem := []byte(s.caCertPem)
+	if s.caCertFile != "" {
+		if s.caCertPem != "" {
+			return nil, errors.New("only one of ca_cert_pem or ca_cert_file can be set")
+		}
+
+		content, err := os.ReadFile(s.caCertFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the ca_cert_file: %w", err)
+		}
+		caCertPem = content
+	}
+
+	if len(caCertPem) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCertPem) {
+			return nil, errors.New("the CA certificate doesn't contain any PEM encoded certificate")
+		}
+		config.RootCAs = pool
+	}
+
+	if s.clientCertPem != "" || s.clientKeyPem != "" {
+		if s.clientCertPem == "" || s.clientKeyPem == "" {
+			return nil, errors.New("client_cert_pem and client_key_pem must be set together")
+		}
+
+		// the error of X509KeyPair doesn't contain the key
+		certificate, err := tls.X509KeyPair([]byte(s.clientCertPem), []byte(s.clientKeyPem))
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{certificate}
+	}
+
+	return config, nil
+}
@@ -0,0 +1,224 @@
+package provider
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/maulid7/terraform-provider-pritunl/pritunl"
+)
+
+// newTLSServer serves the organizations of the Pritunl API over TLS, the clients must present
+// a certificate signed by the clientCA when set
+func newTLSServer(t *testing.T, clientCA *x509.Certificate) *httptest.Server {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id": "5f00000000000000000000a1", "name": "Developers"}]`)
+	}))
+
+	// the rejected handshakes are expected
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+
+	if clientCA != nil {
+		pool := x509.NewCertPool()
+		pool.AddCert(clientCA)
+		server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: pool}
+	}
+
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// serverCAPem returns the self-signed certificate of the server, valid for example.com
+func serverCAPem(server *httptest.Server) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+}
+
+// generateClientCertificate returns a self-signed client certificate and its PEM encoded key
+func generateClientCertificate(t *testing.T) (*x509.Certificate, string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "terraform"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certPem := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPem := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+
+	return certificate, string(certPem), string(keyPem)
+}
+
+// getOrganizations requests the server with the TLS settings
+func getOrganizations(t *testing.T, server *httptest.Server, settings tlsSettings) error {
+	tlsConfig, err := settings.config()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	apiClient := pritunl.NewClient(server.URL, pritunl.WithCredentials("token", "secret"), pritunl.WithTLSConfig(tlsConfig))
+	_, err = apiClient.GetOrganizations()
+
+	return err
+}
+
+func TestTLSCustomCA(t *testing.T) {
+	server := newTLSServer(t, nil)
+
+	if err := getOrganizations(t, server, tlsSettings{}); err == nil || !strings.Contains(err.Error(), "certificate") {
+		t.Errorf("expected the system CAs to reject the certificate, got %v", err)
+	}
+
+	if err := getOrganizations(t, server, tlsSettings{caCertPem: serverCAPem(server)}); err != nil {
+		t.Errorf("expected the CA to be trusted: %s", err)
+	}
+
+	caCertFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caCertFile, []byte(serverCAPem(server)), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := getOrganizations(t, server, tlsSettings{caCertFile: caCertFile}); err != nil {
+		t.Errorf("expected the CA file to be trusted: %s", err)
+	}
+}
+
+func TestTLSServerName(t *testing.T) {
+	server := newTLSServer(t, nil)
+
+	settings := tlsSettings{caCertPem: serverCAPem(server), serverName: "example.com"}
+	if err := getOrganizations(t, server, settings); err != nil {
+		t.Errorf("expected the certificate to be valid for example.com: %s", err)
+	}
+
+	settings.serverName = "vpn.example.org"
+	if err := getOrganizations(t, server, settings); err == nil || !strings.Contains(err.Error(), "vpn.example.org") {
+		t.Errorf("expected the certificate to be invalid for vpn.example.org, got %v", err)
+	}
+}
+
+func TestTLSMinVersion(t *testing.T) {
+	server := newTLSServer(t, nil)
+	server.TLS.MaxVersion = tls.VersionTLS12
+
+	settings := tlsSettings{caCertPem: serverCAPem(server), minVersion: "1.3"}
+	if err := getOrganizations(t, server, settings); err == nil || !strings.Contains(err.Error(), "protocol version") {
+		t.Errorf("expected TLS 1.2 to be rejected, got %v", err)
+	}
+
+	settings.minVersion = "1.2"
+	if err := getOrganizations(t, server, settings); err != nil {
+		t.Errorf("expected TLS 1.2 to be accepted: %s", err)
+	}
+}
+
+func TestTLSClientCertificate(t *testing.T) {
+	clientCA, certPem, keyPem := generateClientCertificate(t)
+	server := newTLSServer(t, clientCA)
+
+	if err := getOrganizations(t, server, tlsSettings{caCertPem: serverCAPem(server)}); err == nil {
+		t.Error("expected the request without a client certificate to be rejected")
+	}
+
+	settings := tlsSettings{caCertPem: serverCAPem(server), clientCertPem: certPem, clientKeyPem: keyPem}
+	if err := getOrganizations(t, server, settings); err != nil {
+		t.Errorf("expected the client certificate to be accepted: %s", err)
+	}
+}
+
+func TestTLSSettingsEnvironment(t *testing.T) {
+	getenv := func(name string) string {
+		if name == "PRITUNL_CA_CERT_FILE" {
+			return "env-ca.pem"
+		}
+		return ""
+	}
+
+	tests := []struct {
+		name     string
+		settings tlsSettings
+		expected tlsSettings
+	}{
+		{"unset", tlsSettings{}, tlsSettings{caCertFile: "env-ca.pem"}},
+		{"configured pem", tlsSettings{caCertPem: "pem"}, tlsSettings{caCertPem: "pem"}},
+		{"configured file", tlsSettings{caCertFile: "ca.pem"}, tlsSettings{caCertFile: "ca.pem"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if settings := test.settings.withEnvironment(getenv); settings != test.expected {
+				t.Errorf("expected %+v, got %+v", test.expected, settings)
+			}
+		})
+	}
+
+	// the variable doesn't conflict with a configured CA
+	server := newTLSServer(t, nil)
+	settings := tlsSettings{caCertPem: serverCAPem(server)}.withEnvironment(getenv)
+	if err := getOrganizations(t, server, settings); err != nil {
+		t.Errorf("expected the configured CA to be used: %s", err)
+	}
+}
+
+func TestTLSSettingsInvalid(t *testing.T) {
+	_, certPem, _ := generateClientCertificate(t)
+	_, _, otherKeyPem := generateClientCertificate(t)
+
+	tests := []struct {
+		name     string
+		settings tlsSettings
+		err      string
+	}{
+		{"ca conflict", tlsSettings{caCertPem: certPem, caCertFile: "ca.pem"}, "only one of ca_cert_pem or ca_cert_file"},
+		{"ca not pem", tlsSettings{caCertPem: "not a certificate"}, "doesn't contain any PEM encoded certificate"},
+		{"missing key", tlsSettings{clientCertPem: certPem}, "must be set together"},
+		{"mismatched key", tlsSettings{clientCertPem: certPem, clientKeyPem: otherKeyPem}, "invalid client certificate"},
+		{"version", tlsSettings{minVersion: "1.4"}, "unsupported min_tls_version"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := test.settings.config()
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("expected %q, got %v", test.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package pritunl
+
+import (
+	"encoding/json"
+)
+
+type Administrator struct {
+	ID        string          `json:"id,omitempty"`
+	Username  string          `json:"username"`
+	Password  WriteOnlyString `json:"password,omitempty"`
+	SuperUser bool            `json:"super_user"`
+	AuthApi   bool            `json:"auth_api"`
+	Token     string          `json:"token,omitempty"`
+	Secret    string          `json:"secret,omitempty"`
+	YubikeyId string          `json:"yubikey_id"`
+	OtpAuth   bool            `json:"otp_auth"`
+	OtpSecret string          `json:"otp_secret,omitempty"`
+	Disabled  bool            `json:"disabled"`
+	Default   bool            `json:"default,omitempty"`
+
+	// Ask the API to generate a new API token and secret on update
+	RegenerateApiCredentials bool `json:"-"`
+}
+
+// MarshalJSON encodes an administrator for create and update requests.
+//
+// The API generates the token, secret and OTP secret itself and treats any true value of
+// these attributes as a request to regenerate them, so the values read from the API are
+// never sent back.
+func (a *Administrator) MarshalJSON() ([]byte, error) {
+	type Alias Administrator
+	data, err := json.Marshal((*Alias)(a))
+	if err != nil {
+		return nil, err
+	}
+
+	var attributes map[string]interface{}
+	err = json.Unmarshal(data, &attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range []string{"token", "secret", "otp_secret", "default"} {
+		delete(attributes, name)
+	}
+
+	if a.RegenerateApiCredentials {
+		attributes["token"] = true
+		attributes["secret"] = true
+	}
+
+	return json.Marshal(attributes)
+}
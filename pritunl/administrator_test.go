@@ -0,0 +1,55 @@
+package pritunl
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestAdministratorMarshalJSON(t *testing.T) {
+	t.Run("never sends generated credentials back", func(t *testing.T) {
+		administrator := &Administrator{
+			Username:  "admin",
+			Token:     "token",
+			Secret:    "secret",
+			OtpSecret: "otp",
+			Default:   true,
+		}
+
+		data, err := json.Marshal(administrator)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		attributes := unmarshalAttributes(t, data)
+		for _, name := range []string{"token", "secret", "otp_secret", "default", "password"} {
+			if _, ok := attributes[name]; ok {
+				t.Errorf("expected %s to be omitted in %s", name, data)
+			}
+		}
+		if attributes["super_user"] != false {
+			t.Errorf("expected super_user to be sent, got %v", attributes["super_user"])
+		}
+	})
+
+	t.Run("requests new credentials", func(t *testing.T) {
+		administrator := &Administrator{
+			Username:                 "admin",
+			Password:                 "password",
+			Token:                    "token",
+			RegenerateApiCredentials: true,
+		}
+
+		data, err := json.Marshal(administrator)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		attributes := unmarshalAttributes(t, data)
+		if attributes["token"] != true || attributes["secret"] != true {
+			t.Errorf("expected token and secret to be true in %s", data)
+		}
+		if attributes["password"] != "password" {
+			t.Errorf("expected password to be sent in %s", data)
+		}
+	})
+}
@@ -2,6 +2,7 @@ package pritunl
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
@@ -10,6 +11,10 @@ import (
 )
 
 type Client interface {
+	// WithContext returns a copy of the client sending its requests with ctx, e.g. to cancel
+	// them or to pass the context to the Logger. The requests use context.Background otherwise.
+	WithContext(ctx context.Context) Client
+
 	TestApiCall() error
 
 	GetOrganizations() ([]Organization, error)
@@ -18,6 +23,7 @@ type Client interface {
 	UpdateOrganization(id string, organization *Organization) error
 	DeleteOrganization(name string) error
 
+	GetUsers(orgId string) ([]User, error)
 	GetUser(id string, orgId string) (*User, error)
 	CreateUser(newUser User) (*User, error)
 	UpdateUser(id string, user *User) error
@@ -25,7 +31,7 @@ type Client interface {
 
 	GetServers() ([]Server, error)
 	GetServer(id string) (*Server, error)
-	CreateServer(serverData map[string]interface{}) (*Server, error)
+	CreateServer(request ServerCreateRequest) (*Server, error)
 	UpdateServer(id string, server *Server) error
 	DeleteServer(id string) error
 
@@ -40,22 +46,52 @@ type Client interface {
 	UpdateRouteOnServer(serverId string, route Route) error
 
 	GetHosts() ([]Host, error)
+	GetHost(id string) (*Host, error)
+	UpdateHost(id string, host *Host) error
+	DeleteHost(id string) error
 	GetHostsByServer(serverId string) ([]Host, error)
 	AttachHostToServer(hostId, serverId string) error
 	DetachHostFromServer(hostId, serverId string) error
 
+	GetHostUsage(hostId, period string) (*HostUsage, error)
+	GetServerBandwidth(serverId, period string) (*ServerBandwidth, error)
+
 	StartServer(serverId string) error
 	StopServer(serverId string) error
+	GetServerOutput(serverId string) (*ServerOutput, error)
+	GetServerLinkOutput(serverId string) (*ServerOutput, error)
+
+	GetSettings() (*Settings, error)
+	UpdateSettings(settings *Settings) error
+
+	GetAdministrators() ([]Administrator, error)
+	GetAdministrator(id string) (*Administrator, error)
+	CreateAdministrator(administrator *Administrator) (*Administrator, error)
+	UpdateAdministrator(id string, administrator *Administrator) (*Administrator, error)
+	DeleteAdministrator(id string) error
 }
 
 type client struct {
 	httpClient *http.Client
 	baseUrl    string
+	ctx        context.Context
+}
+
+func (c client) WithContext(ctx context.Context) Client {
+	c.ctx = ctx
+	return &c
+}
+
+func (c client) context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
 }
 
 func (c client) TestApiCall() error {
 	url := fmt.Sprintf("/state")
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(c.context(), "GET", url, nil)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -78,7 +114,7 @@ func (c client) TestApiCall() error {
 
 func (c client) GetOrganization(id string) (*Organization, error) {
 	url := fmt.Sprintf("/organization/%s", id)
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(c.context(), "GET", url, nil)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -103,7 +139,7 @@ func (c client) GetOrganization(id string) (*Organization, error) {
 
 func (c client) GetOrganizations() ([]Organization, error) {
 	url := fmt.Sprintf("/organization")
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(c.context(), "GET", url, nil)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -130,7 +166,7 @@ func (c client) CreateOrganization(name string) (*Organization, error) {
 	var jsonStr = []byte(`{"name": "` + name + `"}`)
 
 	url := "/organization"
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonStr))
+	req, err := http.NewRequestWithContext(c.context(), "POST", url, bytes.NewBuffer(jsonStr))
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -159,7 +195,7 @@ func (c client) UpdateOrganization(id string, organization *Organization) error
 	}
 
 	url := fmt.Sprintf("/organization/%s", id)
-	req, err := http.NewRequest("PUT", url, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(c.context(), "PUT", url, bytes.NewBuffer(jsonData))
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -177,7 +213,7 @@ func (c client) UpdateOrganization(id string, organization *Organization) error
 
 func (c client) DeleteOrganization(id string) error {
 	url := fmt.Sprintf("/organization/%s", id)
-	req, err := http.NewRequest("DELETE", url, nil)
+	req, err := http.NewRequestWithContext(c.context(), "DELETE", url, nil)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -195,7 +231,7 @@ func (c client) DeleteOrganization(id string) error {
 
 func (c client) GetServer(id string) (*Server, error) {
 	url := fmt.Sprintf("/server/%s", id)
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(c.context(), "GET", url, nil)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -220,7 +256,7 @@ func (c client) GetServer(id string) (*Server, error) {
 
 func (c client) GetServers() ([]Server, error) {
 	url := fmt.Sprintf("/server")
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(c.context(), "GET", url, nil)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -243,178 +279,14 @@ func (c client) GetServers() ([]Server, error) {
 	return servers, nil
 }
 
-func (c client) CreateServer(serverData map[string]interface{}) (*Server, error) {
-	serverStruct := Server{}
-
-	if v, ok := serverData["name"]; ok {
-		serverStruct.Name = v.(string)
-	}
-	if v, ok := serverData["protocol"]; ok {
-		serverStruct.Protocol = v.(string)
-	}
-	if v, ok := serverData["cipher"]; ok {
-		serverStruct.Cipher = v.(string)
-	}
-	if v, ok := serverData["network"]; ok {
-		serverStruct.Network = v.(string)
-	}
-	if v, ok := serverData["hash"]; ok {
-		serverStruct.Hash = v.(string)
-	}
-	if v, ok := serverData["port"]; ok {
-		serverStruct.Port = v.(int)
-	}
-	if v, ok := serverData["bind_address"]; ok {
-		serverStruct.BindAddress = v.(string)
-	}
-	if v, ok := serverData["groups"]; ok {
-		groups := make([]string, 0)
-		for _, group := range v.([]interface{}) {
-			groups = append(groups, group.(string))
-		}
-		serverStruct.Groups = groups
-	}
-	if v, ok := serverData["dns_servers"]; ok {
-		dnsServers := make([]string, 0)
-		for _, dns := range v.([]interface{}) {
-			dnsServers = append(dnsServers, dns.(string))
-		}
-		serverStruct.DnsServers = dnsServers
-	}
-	if v, ok := serverData["network_wg"]; ok {
-		serverStruct.NetworkWG = v.(string)
-	}
-	if v, ok := serverData["port_wg"]; ok {
-		serverStruct.PortWG = v.(int)
-	}
-
-	isWgEnabled := serverStruct.NetworkWG != "" && serverStruct.PortWG > 0
-	serverStruct.WG = isWgEnabled
-
-	if v, ok := serverData["sso_auth"]; ok {
-		serverStruct.SsoAuth = v.(bool)
-	}
-
-	if v, ok := serverData["otp_auth"]; ok {
-		serverStruct.OtpAuth = v.(bool)
-	}
-
-	if v, ok := serverData["device_auth"]; ok {
-		serverStruct.DeviceAuth = v.(bool)
-	}
-
-	if v, ok := serverData["dynamic_firewall"]; ok {
-		serverStruct.DynamicFirewall = v.(bool)
-	}
-
-	if v, ok := serverData["ipv6"]; ok {
-		serverStruct.IPv6 = v.(bool)
-	}
-
-	if v, ok := serverData["dh_param_bits"]; ok {
-		serverStruct.DhParamBits = v.(int)
-	}
-
-	if v, ok := serverData["ping_interval"]; ok {
-		serverStruct.PingInterval = v.(int)
-	}
-
-	if v, ok := serverData["ping_timeout"]; ok {
-		serverStruct.PingTimeout = v.(int)
-	}
-
-	if v, ok := serverData["link_ping_interval"]; ok {
-		serverStruct.LinkPingInterval = v.(int)
-	}
-
-	if v, ok := serverData["link_ping_timeout"]; ok {
-		serverStruct.LinkPingTimeout = v.(int)
-	}
-
-	if v, ok := serverData["session_timeout"]; ok {
-		serverStruct.SessionTimeout = v.(int)
-	}
-
-	if v, ok := serverData["inactive_timeout"]; ok {
-		serverStruct.InactiveTimeout = v.(int)
-	}
-
-	if v, ok := serverData["max_clients"]; ok {
-		serverStruct.MaxClients = v.(int)
-	}
-
-	if v, ok := serverData["network_mode"]; ok {
-		serverStruct.NetworkMode = v.(string)
-	}
-
-	if v, ok := serverData["network_start"]; ok {
-		serverStruct.NetworkStart = v.(string)
-	}
-
-	if v, ok := serverData["network_end"]; ok {
-		serverStruct.NetworkEnd = v.(string)
-	}
-
-	if serverStruct.NetworkMode == ServerNetworkModeBridge && (serverStruct.NetworkStart == "" || serverStruct.NetworkEnd == "") {
-		return nil, fmt.Errorf("the attribute network_mode = %s requires network_start and network_end attributes", ServerNetworkModeBridge)
-	}
-
-	if v, ok := serverData["mss_fix"]; ok {
-		serverStruct.MssFix = v.(int)
-	}
-
-	if v, ok := serverData["max_devices"]; ok {
-		serverStruct.MaxDevices = v.(int)
-	}
-
-	if v, ok := serverData["pre_connect_msg"]; ok {
-		serverStruct.PreConnectMsg = v.(string)
-	}
-
-	if v, ok := serverData["allowed_devices"]; ok {
-		serverStruct.AllowedDevices = v.(string)
-	}
-
-	if v, ok := serverData["search_domain"]; ok {
-		serverStruct.SearchDomain = v.(string)
-	}
-
-	if v, ok := serverData["replica_count"]; ok {
-		serverStruct.ReplicaCount = v.(int)
-	}
-
-	if v, ok := serverData["multi_device"]; ok {
-		serverStruct.MultiDevice = v.(bool)
-	}
-
-	if v, ok := serverData["debug"]; ok {
-		serverStruct.Debug = v.(bool)
-	}
-
-	if v, ok := serverData["restrict_routes"]; ok {
-		serverStruct.RestrictRoutes = v.(bool)
-	}
-
-	if v, ok := serverData["block_outside_dns"]; ok {
-		serverStruct.BlockOutsideDns = v.(bool)
-	}
-
-	if v, ok := serverData["dns_mapping"]; ok {
-		serverStruct.DnsMapping = v.(bool)
-	}
-
-	if v, ok := serverData["inter_client"]; ok {
-		serverStruct.InterClient = v.(bool)
-	}
-
-	if v, ok := serverData["vxlan"]; ok {
-		serverStruct.VxLan = v.(bool)
+func (c client) CreateServer(request ServerCreateRequest) (*Server, error) {
+	jsonData, err := request.Server().MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("CreateServer: Error on marshalling data: %s", err)
 	}
 
-	jsonData, err := serverStruct.MarshalJSON()
-
 	url := "/server"
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(c.context(), "POST", url, bytes.NewBuffer(jsonData))
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -443,7 +315,7 @@ func (c client) UpdateServer(id string, server *Server) error {
 	}
 
 	url := fmt.Sprintf("/server/%s", id)
-	req, err := http.NewRequest("PUT", url, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(c.context(), "PUT", url, bytes.NewBuffer(jsonData))
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -461,7 +333,7 @@ func (c client) UpdateServer(id string, server *Server) error {
 
 func (c client) DeleteServer(id string) error {
 	url := fmt.Sprintf("/server/%s", id)
-	req, err := http.NewRequest("DELETE", url, nil)
+	req, err := http.NewRequestWithContext(c.context(), "DELETE", url, nil)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -479,7 +351,7 @@ func (c client) DeleteServer(id string) error {
 
 func (c client) GetOrganizationsByServer(serverId string) ([]Organization, error) {
 	url := fmt.Sprintf("/server/%s/organization", serverId)
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(c.context(), "GET", url, nil)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -500,7 +372,7 @@ func (c client) GetOrganizationsByServer(serverId string) ([]Organization, error
 
 func (c client) AttachOrganizationToServer(organizationId, serverId string) error {
 	url := fmt.Sprintf("/server/%s/organization/%s", serverId, organizationId)
-	req, err := http.NewRequest("PUT", url, nil)
+	req, err := http.NewRequestWithContext(c.context(), "PUT", url, nil)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -518,7 +390,7 @@ func (c client) AttachOrganizationToServer(organizationId, serverId string) erro
 
 func (c client) DetachOrganizationFromServer(organizationId, serverId string) error {
 	url := fmt.Sprintf("/server/%s/organization/%s", serverId, organizationId)
-	req, err := http.NewRequest("DELETE", url, nil)
+	req, err := http.NewRequestWithContext(c.context(), "DELETE", url, nil)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -536,7 +408,7 @@ func (c client) DetachOrganizationFromServer(organizationId, serverId string) er
 
 func (c client) StartServer(serverId string) error {
 	url := fmt.Sprintf("/server/%s/operation/start", serverId)
-	req, err := http.NewRequest("PUT", url, nil)
+	req, err := http.NewRequestWithContext(c.context(), "PUT", url, nil)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -554,7 +426,7 @@ func (c client) StartServer(serverId string) error {
 
 func (c client) StopServer(serverId string) error {
 	url := fmt.Sprintf("/server/%s/operation/stop", serverId)
-	req, err := http.NewRequest("PUT", url, nil)
+	req, err := http.NewRequestWithContext(c.context(), "PUT", url, nil)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -570,9 +442,57 @@ func (c client) StopServer(serverId string) error {
 	return nil
 }
 
+func (c client) GetServerOutput(serverId string) (*ServerOutput, error) {
+	url := fmt.Sprintf("/server/%s/output", serverId)
+	req, err := http.NewRequestWithContext(c.context(), "GET", url, nil)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("GetServerOutput: Error on HTTP request: %s", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("Non-200 response on getting the server output\nbody=%s", body)
+	}
+
+	var output ServerOutput
+	err = json.Unmarshal(body, &output)
+	if err != nil {
+		return nil, fmt.Errorf("GetServerOutput: %s: %+v, id=%s, body=%s", err, output, serverId, body)
+	}
+
+	return &output, nil
+}
+
+func (c client) GetServerLinkOutput(serverId string) (*ServerOutput, error) {
+	url := fmt.Sprintf("/server/%s/link_output", serverId)
+	req, err := http.NewRequestWithContext(c.context(), "GET", url, nil)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("GetServerLinkOutput: Error on HTTP request: %s", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("Non-200 response on getting the server link output\nbody=%s", body)
+	}
+
+	var output ServerOutput
+	err = json.Unmarshal(body, &output)
+	if err != nil {
+		return nil, fmt.Errorf("GetServerLinkOutput: %s: %+v, id=%s, body=%s", err, output, serverId, body)
+	}
+
+	return &output, nil
+}
+
 func (c client) GetRoutesByServer(serverId string) ([]Route, error) {
 	url := fmt.Sprintf("/server/%s/route", serverId)
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(c.context(), "GET", url, nil)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -595,7 +515,7 @@ func (c client) AddRouteToServer(serverId string, route Route) (*Route, error) {
 	jsonData, err := json.Marshal(route)
 
 	url := fmt.Sprintf("/server/%s/route", serverId)
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(c.context(), "POST", url, bytes.NewBuffer(jsonData))
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -620,7 +540,7 @@ func (c client) AddRoutesToServer(serverId string, routes []Route) error {
 	jsonData, err := json.Marshal(routes)
 
 	url := fmt.Sprintf("/server/%s/routes", serverId)
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(c.context(), "POST", url, bytes.NewBuffer(jsonData))
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -640,7 +560,7 @@ func (c client) UpdateRouteOnServer(serverId string, route Route) error {
 	jsonData, err := json.Marshal(route)
 
 	url := fmt.Sprintf("/server/%s/route/%s", serverId, route.GetID())
-	req, err := http.NewRequest("PUT", url, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(c.context(), "PUT", url, bytes.NewBuffer(jsonData))
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -658,7 +578,7 @@ func (c client) UpdateRouteOnServer(serverId string, route Route) error {
 
 func (c client) DeleteRouteFromServer(serverId string, route Route) error {
 	url := fmt.Sprintf("/server/%s/route/%s", serverId, route.GetID())
-	req, err := http.NewRequest("DELETE", url, nil)
+	req, err := http.NewRequestWithContext(c.context(), "DELETE", url, nil)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -674,9 +594,33 @@ func (c client) DeleteRouteFromServer(serverId string, route Route) error {
 	return nil
 }
 
+func (c client) GetUsers(orgId string) ([]User, error) {
+	url := fmt.Sprintf("/user/%s", orgId)
+	req, err := http.NewRequestWithContext(c.context(), "GET", url, nil)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("GetUsers: Error on HTTP request: %s", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("Non-200 response on getting the users\nbody=%s", body)
+	}
+
+	var users []User
+	err = json.Unmarshal(body, &users)
+	if err != nil {
+		return nil, fmt.Errorf("GetUsers: %s: orgId=%s, body=%s", err, orgId, body)
+	}
+
+	return users, nil
+}
+
 func (c client) GetUser(id string, orgId string) (*User, error) {
 	url := fmt.Sprintf("/user/%s/%s", orgId, id)
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(c.context(), "GET", url, nil)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -699,13 +643,13 @@ func (c client) GetUser(id string, orgId string) (*User, error) {
 }
 
 func (c client) CreateUser(newUser User) (*User, error) {
-	jsonData, err := json.Marshal(newUser)
+	jsonData, err := json.Marshal(&newUser)
 	if err != nil {
 		return nil, fmt.Errorf("CreateUser: Error on marshalling data: %s", err)
 	}
 
 	url := fmt.Sprintf("/user/%s", newUser.Organization)
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(c.context(), "POST", url, bytes.NewBuffer(jsonData))
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -738,7 +682,7 @@ func (c client) UpdateUser(id string, user *User) error {
 	}
 
 	url := fmt.Sprintf("/user/%s/%s", user.Organization, id)
-	req, err := http.NewRequest("PUT", url, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(c.context(), "PUT", url, bytes.NewBuffer(jsonData))
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -756,7 +700,7 @@ func (c client) UpdateUser(id string, user *User) error {
 
 func (c client) DeleteUser(id string, orgId string) error {
 	url := fmt.Sprintf("/user/%s/%s", orgId, id)
-	req, err := http.NewRequest("DELETE", url, nil)
+	req, err := http.NewRequestWithContext(c.context(), "DELETE", url, nil)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -774,7 +718,7 @@ func (c client) DeleteUser(id string, orgId string) error {
 
 func (c client) GetHosts() ([]Host, error) {
 	url := fmt.Sprintf("/host")
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(c.context(), "GET", url, nil)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -797,9 +741,74 @@ func (c client) GetHosts() ([]Host, error) {
 	return hosts, nil
 }
 
+func (c client) GetHost(id string) (*Host, error) {
+	url := fmt.Sprintf("/host/%s", id)
+	req, err := http.NewRequestWithContext(c.context(), "GET", url, nil)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("GetHost: Error on HTTP request: %s", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("Non-200 response on getting the host\nbody=%s", body)
+	}
+
+	var host Host
+	err = json.Unmarshal(body, &host)
+	if err != nil {
+		return nil, fmt.Errorf("GetHost: %s: %+v, id=%s, body=%s", err, host, id, body)
+	}
+
+	return &host, nil
+}
+
+func (c client) UpdateHost(id string, host *Host) error {
+	jsonData, err := json.Marshal(host)
+	if err != nil {
+		return fmt.Errorf("UpdateHost: Error on marshalling data: %s", err)
+	}
+
+	url := fmt.Sprintf("/host/%s", id)
+	req, err := http.NewRequestWithContext(c.context(), "PUT", url, bytes.NewBuffer(jsonData))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("UpdateHost: Error on HTTP request: %s", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("Non-200 response on updating the host\nbody=%s", body)
+	}
+
+	return nil
+}
+
+func (c client) DeleteHost(id string) error {
+	url := fmt.Sprintf("/host/%s", id)
+	req, err := http.NewRequestWithContext(c.context(), "DELETE", url, nil)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("DeleteHost: Error on HTTP request: %s", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("Non-200 response on deleting the host\nbody=%s", body)
+	}
+
+	return nil
+}
+
 func (c client) GetHostsByServer(serverId string) ([]Host, error) {
 	url := fmt.Sprintf("/server/%s/host", serverId)
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(c.context(), "GET", url, nil)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -822,9 +831,57 @@ func (c client) GetHostsByServer(serverId string) ([]Host, error) {
 	return hosts, nil
 }
 
+func (c client) GetHostUsage(hostId, period string) (*HostUsage, error) {
+	url := fmt.Sprintf("/host/%s/usage/%s", hostId, period)
+	req, err := http.NewRequestWithContext(c.context(), "GET", url, nil)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("GetHostUsage: Error on HTTP request: %s", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("Non-200 response on getting the host usage\nbody=%s", body)
+	}
+
+	var usage HostUsage
+	err = json.Unmarshal(body, &usage)
+	if err != nil {
+		return nil, fmt.Errorf("GetHostUsage: %s: %+v, id=%s, body=%s", err, usage, hostId, body)
+	}
+
+	return &usage, nil
+}
+
+func (c client) GetServerBandwidth(serverId, period string) (*ServerBandwidth, error) {
+	url := fmt.Sprintf("/server/%s/bandwidth/%s", serverId, period)
+	req, err := http.NewRequestWithContext(c.context(), "GET", url, nil)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("GetServerBandwidth: Error on HTTP request: %s", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("Non-200 response on getting the server bandwidth\nbody=%s", body)
+	}
+
+	var bandwidth ServerBandwidth
+	err = json.Unmarshal(body, &bandwidth)
+	if err != nil {
+		return nil, fmt.Errorf("GetServerBandwidth: %s: %+v, id=%s, body=%s", err, bandwidth, serverId, body)
+	}
+
+	return &bandwidth, nil
+}
+
 func (c client) AttachHostToServer(hostId, serverId string) error {
 	url := fmt.Sprintf("/server/%s/host/%s", serverId, hostId)
-	req, err := http.NewRequest("PUT", url, nil)
+	req, err := http.NewRequestWithContext(c.context(), "PUT", url, nil)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -842,7 +899,7 @@ func (c client) AttachHostToServer(hostId, serverId string) error {
 
 func (c client) DetachHostFromServer(hostId, serverId string) error {
 	url := fmt.Sprintf("/server/%s/host/%s", serverId, hostId)
-	req, err := http.NewRequest("DELETE", url, nil)
+	req, err := http.NewRequestWithContext(c.context(), "DELETE", url, nil)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -858,19 +915,222 @@ func (c client) DetachHostFromServer(hostId, serverId string) error {
 	return nil
 }
 
-func NewClient(baseUrl, apiToken, apiSecret string, insecure bool) Client {
-	underlyingTransport := &http.Transport{
-		Proxy: http.ProxyFromEnvironment,
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: insecure},
+// NewClient creates a client for the Pritunl API at baseUrl, e.g. https://vpn.example.com.
+// The requests are signed with the credentials set by WithCredentials.
+func NewClient(baseUrl string, opts ...Option) Client {
+	o := options{logBodies: true}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	underlyingTransport := o.underlyingTransport
+	if underlyingTransport == nil {
+		tlsConfig := &tls.Config{}
+		if o.tlsConfig != nil {
+			tlsConfig = o.tlsConfig.Clone()
+		}
+		if o.insecure {
+			tlsConfig.InsecureSkipVerify = true
+		}
+
+		underlyingTransport = &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
+		}
+	}
+
+	credentialsFunc := o.credentialsFunc
+	if credentialsFunc == nil {
+		credentialsFunc = staticCredentials(o.apiToken, o.apiSecret)
+	}
+
+	userAgent := "pritunl-go"
+	if o.userAgent != "" {
+		userAgent = o.userAgent + " " + userAgent
 	}
+
 	httpClient := &http.Client{
+		Timeout: o.timeout,
 		Transport: &transport{
-			baseUrl:             baseUrl,
-			apiToken:            apiToken,
-			apiSecret:           apiSecret,
+			baseUrls:            append([]string{baseUrl}, o.failoverUrls...),
+			logger:              o.logger,
+			logBodies:           o.logBodies,
+			credentials:         &credentialsCache{fn: credentialsFunc},
+			userAgent:           userAgent,
 			underlyingTransport: underlyingTransport,
 		},
 	}
 
 	return &client{httpClient: httpClient}
 }
+
+func (c client) GetSettings() (*Settings, error) {
+	url := "/settings"
+	req, err := http.NewRequestWithContext(c.context(), "GET", url, nil)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("GetSettings: Error on HTTP request: %s", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("Non-200 response on getting the settings\nbody=%s", body)
+	}
+
+	var settings Settings
+	err = json.Unmarshal(body, &settings)
+	if err != nil {
+		return nil, fmt.Errorf("GetSettings: %s: %+v, body=%s", err, settings, body)
+	}
+
+	return &settings, nil
+}
+
+func (c client) UpdateSettings(settings *Settings) error {
+	jsonData, err := json.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("UpdateSettings: Error on marshalling data: %s", err)
+	}
+
+	url := "/settings"
+	req, err := http.NewRequestWithContext(c.context(), "PUT", url, bytes.NewBuffer(jsonData))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("UpdateSettings: Error on HTTP request: %s", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("Non-200 response on updating the settings\nbody=%s", body)
+	}
+
+	return nil
+}
+
+func (c client) GetAdministrators() ([]Administrator, error) {
+	url := "/admin"
+	req, err := http.NewRequestWithContext(c.context(), "GET", url, nil)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("GetAdministrators: Error on HTTP request: %s", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("Non-200 response on getting the administrators\nbody=%s", body)
+	}
+
+	var administrators []Administrator
+	err = json.Unmarshal(body, &administrators)
+	if err != nil {
+		return nil, fmt.Errorf("GetAdministrators: %s: %+v, body=%s", err, administrators, body)
+	}
+
+	return administrators, nil
+}
+
+func (c client) GetAdministrator(id string) (*Administrator, error) {
+	url := fmt.Sprintf("/admin/%s", id)
+	req, err := http.NewRequestWithContext(c.context(), "GET", url, nil)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("GetAdministrator: Error on HTTP request: %s", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("Non-200 response on getting the administrator\nbody=%s", body)
+	}
+
+	var administrator Administrator
+	err = json.Unmarshal(body, &administrator)
+	if err != nil {
+		return nil, fmt.Errorf("GetAdministrator: %s: id=%s, body=%s", err, id, body)
+	}
+
+	return &administrator, nil
+}
+
+func (c client) CreateAdministrator(administrator *Administrator) (*Administrator, error) {
+	jsonData, err := json.Marshal(administrator)
+	if err != nil {
+		return nil, fmt.Errorf("CreateAdministrator: Error on marshalling data: %s", err)
+	}
+
+	url := "/admin"
+	req, err := http.NewRequestWithContext(c.context(), "POST", url, bytes.NewBuffer(jsonData))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("CreateAdministrator: Error on HTTP request: %s", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("Non-200 response on creating the administrator\nbody=%s", body)
+	}
+
+	var created Administrator
+	err = json.Unmarshal(body, &created)
+	if err != nil {
+		return nil, fmt.Errorf("CreateAdministrator: %s: username=%s, body=%s", err, administrator.Username, body)
+	}
+
+	return &created, nil
+}
+
+func (c client) UpdateAdministrator(id string, administrator *Administrator) (*Administrator, error) {
+	jsonData, err := json.Marshal(administrator)
+	if err != nil {
+		return nil, fmt.Errorf("UpdateAdministrator: Error on marshalling data: %s", err)
+	}
+
+	url := fmt.Sprintf("/admin/%s", id)
+	req, err := http.NewRequestWithContext(c.context(), "PUT", url, bytes.NewBuffer(jsonData))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("UpdateAdministrator: Error on HTTP request: %s", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("Non-200 response on updating the administrator\nbody=%s", body)
+	}
+
+	var updated Administrator
+	err = json.Unmarshal(body, &updated)
+	if err != nil {
+		return nil, fmt.Errorf("UpdateAdministrator: %s: id=%s, body=%s", err, id, body)
+	}
+
+	return &updated, nil
+}
+
+func (c client) DeleteAdministrator(id string) error {
+	url := fmt.Sprintf("/admin/%s", id)
+	req, err := http.NewRequestWithContext(c.context(), "DELETE", url, nil)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("DeleteAdministrator: Error on HTTP request: %s", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("Non-200 response on deleting the administrator\nbody=%s", body)
+	}
+
+	return nil
+}
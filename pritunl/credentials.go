@@ -0,0 +1,47 @@
+package pritunl
+
+import (
+	"sync"
+)
+
+// CredentialsFunc returns the API token and secret the requests are signed with. It's called
+// before the first request and again when the API responds 401 Unauthorized, so credentials
+// rotated while the client is in use are picked up.
+type CredentialsFunc func() (apiToken, apiSecret string, err error)
+
+type credentials struct {
+	apiToken  string
+	apiSecret string
+}
+
+// credentialsCache keeps the credentials of a CredentialsFunc between the requests
+type credentialsCache struct {
+	fn      CredentialsFunc
+	mu      sync.Mutex
+	current *credentials
+}
+
+func staticCredentials(apiToken, apiSecret string) CredentialsFunc {
+	return func() (string, string, error) {
+		return apiToken, apiSecret, nil
+	}
+}
+
+// get returns the cached credentials, fetching them when there are none yet or when
+// the cached ones are the rejected ones. Requests rejected concurrently fetch them once.
+func (c *credentialsCache) get(rejected *credentials) (credentials, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.current != nil && (rejected == nil || *c.current != *rejected) {
+		return *c.current, nil
+	}
+
+	apiToken, apiSecret, err := c.fn()
+	if err != nil {
+		return credentials{}, err
+	}
+
+	c.current = &credentials{apiToken: apiToken, apiSecret: apiSecret}
+	return *c.current, nil
+}
@@ -0,0 +1,106 @@
+package pritunl
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newRotatingServer accepts the requests signed with the token of the valid function and
+// records the bodies of the accepted ones
+func newRotatingServer(t *testing.T, valid func() string, bodies *[]string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Auth-Token") != valid() {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, "Unauthorized")
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		*bodies = append(*bodies, string(body))
+
+		organization := `{"id": "5f00000000000000000000a1", "name": "Developers"}`
+		if r.Method == http.MethodGet {
+			organization = "[" + organization + "]"
+		}
+		fmt.Fprint(w, organization)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestCredentialsRotation(t *testing.T) {
+	validToken := "token-1"
+	var bodies []string
+	server := newRotatingServer(t, func() string { return validToken }, &bodies)
+
+	token := "token-1"
+	calls := 0
+	apiClient := NewClient(server.URL, WithCredentialsFunc(func() (string, string, error) {
+		calls++
+		return token, "secret", nil
+	}))
+
+	if _, err := apiClient.GetOrganizations(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := apiClient.GetOrganizations(); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 {
+		t.Errorf("expected the credentials to be cached, got %d calls", calls)
+	}
+
+	// the credentials are rotated, the POST request is retried with the new ones and its body
+	validToken, token = "token-2", "token-2"
+	if _, err := apiClient.CreateOrganization("Developers"); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Errorf("expected the credentials to be fetched again, got %d calls", calls)
+	}
+	if last := bodies[len(bodies)-1]; !strings.Contains(last, "Developers") {
+		t.Errorf("expected the body to be sent again, got %q", last)
+	}
+}
+
+func TestCredentialsUnauthorized(t *testing.T) {
+	var bodies []string
+	server := newRotatingServer(t, func() string { return "valid" }, &bodies)
+
+	calls := 0
+	apiClient := NewClient(server.URL, WithCredentialsFunc(func() (string, string, error) {
+		calls++
+		return "revoked", "secret", nil
+	}))
+
+	// unchanged credentials aren't retried
+	if _, err := apiClient.GetOrganizations(); err == nil || !strings.Contains(err.Error(), "Unauthorized") {
+		t.Errorf("expected the 401 response, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected the credentials to be fetched twice, got %d calls", calls)
+	}
+	if len(bodies) != 0 {
+		t.Errorf("expected no accepted request, got %d", len(bodies))
+	}
+}
+
+func TestCredentialsFuncError(t *testing.T) {
+	var bodies []string
+	server := newRotatingServer(t, func() string { return "valid" }, &bodies)
+
+	apiClient := NewClient(server.URL, WithCredentialsFunc(func() (string, string, error) {
+		return "", "", errors.New("vault is sealed")
+	}))
+
+	_, err := apiClient.GetOrganizations()
+	if err == nil || !strings.Contains(err.Error(), "failed to get the API credentials: vault is sealed") {
+		t.Errorf("expected the credentials error, got %v", err)
+	}
+}
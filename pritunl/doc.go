@@ -0,0 +1,26 @@
+// Package pritunl is a client for the Pritunl API.
+//
+// The client signs every request with the API token and secret of a Pritunl administrator,
+// the API access has to be enabled for the administrator in the Pritunl settings:
+//
+//	client := pritunl.NewClient("https://vpn.example.com",
+//		pritunl.WithCredentials(os.Getenv("PRITUNL_TOKEN"), os.Getenv("PRITUNL_SECRET")),
+//	)
+//
+//	organizations, err := client.GetOrganizations()
+//
+// The Terraform provider for Pritunl uses the package to manage the Pritunl objects, so
+// the Client interface covers every endpoint the provider needs.
+//
+// # Versioning
+//
+// The package is part of the provider module and follows the semantic versioning of its
+// vX.Y.Z release tags:
+//
+//   - patch releases only fix bugs
+//   - minor releases may add functions, options, model fields and methods of the Client
+//     interface. Implementations of Client outside the package, e.g. test fakes, should
+//     embed the interface to keep compiling.
+//   - the existing functions, options, models and methods only change or go away in a
+//     major release
+package pritunl
@@ -0,0 +1,92 @@
+package pritunl_test
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"time"
+
+	"github.com/maulid7/terraform-provider-pritunl/pritunl"
+)
+
+// newExampleServer serves a Pritunl API with the Developers organization and its users
+func newExampleServer() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/organization", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id": "5f00000000000000000000a1", "name": "Developers", "auth_api": false, "user_count": 1}]`)
+	})
+	mux.HandleFunc("/user/5f00000000000000000000a1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			body, _ := io.ReadAll(r.Body)
+			fmt.Fprintf(w, `[{"id": "5f00000000000000000000b2", "organization": "5f00000000000000000000a1", "request": %s}]`, body)
+			return
+		}
+		fmt.Fprint(w, `[{"id": "5f00000000000000000000b1", "organization": "5f00000000000000000000a1", "name": "alice", "email": "alice@example.com"}]`)
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func ExampleNewClient() {
+	client := pritunl.NewClient("https://vpn.example.com",
+		pritunl.WithCredentials(os.Getenv("PRITUNL_TOKEN"), os.Getenv("PRITUNL_SECRET")),
+		pritunl.WithTimeout(30*time.Second),
+		pritunl.WithUserAgent("onboarding-bot/1.2.0"),
+	)
+
+	// checks the API is reachable and the credentials are valid
+	if err := client.TestApiCall(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func ExampleClient_GetOrganizations() {
+	server := newExampleServer()
+	defer server.Close()
+
+	client := pritunl.NewClient(server.URL, pritunl.WithCredentials("token", "secret"))
+
+	organizations, err := client.GetOrganizations()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, organization := range organizations {
+		users, err := client.GetUsers(organization.ID)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		for _, user := range users {
+			fmt.Printf("%s/%s %s\n", organization.Name, user.Name, user.Email)
+		}
+	}
+
+	// Output:
+	// Developers/alice alice@example.com
+}
+
+func ExampleClient_CreateUser() {
+	server := newExampleServer()
+	defer server.Close()
+
+	client := pritunl.NewClient(server.URL, pritunl.WithCredentials("token", "secret"))
+
+	user, err := client.CreateUser(pritunl.User{
+		Name:         "bob",
+		Organization: "5f00000000000000000000a1",
+		Email:        "bob@example.com",
+		Groups:       []string{"developers"},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(user.ID)
+
+	// Output:
+	// 5f00000000000000000000b2
+}
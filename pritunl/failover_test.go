@@ -0,0 +1,141 @@
+package pritunl
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// clusterHost serves the organizations with the status and records the request bodies
+type clusterHost struct {
+	*httptest.Server
+	status int
+	bodies []string
+}
+
+func newClusterHost(t *testing.T, status int) *clusterHost {
+	host := &clusterHost{status: status}
+	host.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		host.bodies = append(host.bodies, string(body))
+
+		w.WriteHeader(host.status)
+		if r.Method == http.MethodGet {
+			fmt.Fprint(w, `[{"id": "5f00000000000000000000a1", "name": "Developers"}]`)
+		} else {
+			fmt.Fprint(w, `{"id": "5f00000000000000000000a1", "name": "Developers"}`)
+		}
+	}))
+	t.Cleanup(host.Close)
+
+	return host
+}
+
+func TestFailover(t *testing.T) {
+	down := newClusterHost(t, http.StatusOK)
+	down.Close()
+	patching := newClusterHost(t, http.StatusServiceUnavailable)
+	healthy := newClusterHost(t, http.StatusOK)
+
+	logger := &recordingLogger{level: LogLevelDebug}
+	apiClient := NewClient(down.URL,
+		WithCredentials("token", "secret"),
+		WithFailoverUrls(patching.URL, healthy.URL),
+		WithStructuredLogger(logger),
+	)
+
+	if _, err := apiClient.GetOrganizations(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(patching.bodies) != 1 || len(healthy.bodies) != 1 {
+		t.Fatalf("expected a request to each running host, got %d and %d", len(patching.bodies), len(healthy.bodies))
+	}
+
+	var failovers, served []interface{}
+	for _, entry := range logger.entries {
+		switch entry.message {
+		case "Failing over to the next API URL":
+			failovers = append(failovers, entry.fields["endpoint"])
+		case "Received HTTP response":
+			if entry.fields["status"] == http.StatusOK {
+				served = append(served, entry.fields["endpoint"])
+			}
+		}
+	}
+	if expected := []interface{}{down.URL, patching.URL}; !reflect.DeepEqual(failovers, expected) {
+		t.Errorf("expected the failovers from %q, got %q", expected, failovers)
+	}
+	if expected := []interface{}{healthy.URL}; !reflect.DeepEqual(served, expected) {
+		t.Errorf("expected the request to be served by %q, got %q", expected, served)
+	}
+
+	// the host that served the last request is tried first
+	if _, err := apiClient.GetOrganizations(); err != nil {
+		t.Fatal(err)
+	}
+	if len(patching.bodies) != 1 || len(healthy.bodies) != 2 {
+		t.Errorf("expected the request to be sent to the healthy host only, got %d and %d", len(patching.bodies), len(healthy.bodies))
+	}
+}
+
+func TestFailoverNotIdempotent(t *testing.T) {
+	down := newClusterHost(t, http.StatusOK)
+	down.Close()
+	failing := newClusterHost(t, http.StatusInternalServerError)
+	healthy := newClusterHost(t, http.StatusOK)
+
+	// the request wasn't sent to the host that is down, so it is sent to the next one
+	apiClient := NewClient(down.URL, WithCredentials("token", "secret"), WithFailoverUrls(healthy.URL))
+	if _, err := apiClient.CreateOrganization("Developers"); err != nil {
+		t.Fatal(err)
+	}
+	if len(healthy.bodies) != 1 || !strings.Contains(healthy.bodies[0], "Developers") {
+		t.Errorf("expected the body to be sent to the healthy host, got %q", healthy.bodies)
+	}
+
+	// the failing host may have created the organization, it isn't created again
+	apiClient = NewClient(failing.URL, WithCredentials("token", "secret"), WithFailoverUrls(healthy.URL))
+	if _, err := apiClient.CreateOrganization("Developers"); err == nil {
+		t.Error("expected the 500 response")
+	}
+	if len(failing.bodies) != 1 || len(healthy.bodies) != 1 {
+		t.Errorf("expected no failover after the 500 response, got %d and %d requests", len(failing.bodies), len(healthy.bodies))
+	}
+}
+
+func TestFailoverAllFailing(t *testing.T) {
+	first := newClusterHost(t, http.StatusBadGateway)
+	second := newClusterHost(t, http.StatusInternalServerError)
+
+	apiClient := NewClient(first.URL, WithCredentials("token", "secret"), WithFailoverUrls(second.URL))
+
+	// the response of the last host is returned
+	_, err := apiClient.GetOrganizations()
+	if err == nil || !strings.Contains(err.Error(), "Non-200 response") {
+		t.Errorf("expected the 500 response, got %v", err)
+	}
+	if len(first.bodies) != 1 || len(second.bodies) != 1 {
+		t.Errorf("expected a request to each host, got %d and %d", len(first.bodies), len(second.bodies))
+	}
+}
+
+func TestFailoverStatuses(t *testing.T) {
+	for _, status := range []int{http.StatusNotFound, http.StatusInternalServerError} {
+		first := newClusterHost(t, status)
+		second := newClusterHost(t, http.StatusOK)
+
+		apiClient := NewClient(first.URL, WithCredentials("token", "secret"), WithFailoverUrls(second.URL))
+
+		if _, err := apiClient.GetOrganizations(); err == nil {
+			t.Errorf("expected the %d response", status)
+		}
+		if len(second.bodies) != 0 {
+			t.Errorf("expected no failover on a %d response, got %d requests", status, len(second.bodies))
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package pritunl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// LogLevel is the level of the messages of the client
+type LogLevel int
+
+const (
+	// LogLevelTrace is the level of the request and response bodies
+	LogLevelTrace LogLevel = iota
+	// LogLevelDebug is the level of the requests, the responses and the failovers
+	LogLevelDebug
+)
+
+func (l LogLevel) String() string {
+	if l == LogLevelTrace {
+		return "TRACE"
+	}
+	return "DEBUG"
+}
+
+// Logger receives the structured messages of the client, see WithStructuredLogger
+type Logger interface {
+	// Enabled reports if the messages of the level are logged. The bodies are only read and
+	// redacted when LogLevelTrace is enabled.
+	Enabled(level LogLevel) bool
+
+	// Log logs the message with the fields, ctx is the context of the request
+	Log(ctx context.Context, level LogLevel, message string, fields map[string]interface{})
+}
+
+// printfLogger logs the DEBUG messages with a standard logger, see WithLogger
+type printfLogger struct {
+	logger *log.Logger
+}
+
+func (l printfLogger) Enabled(level LogLevel) bool {
+	return level == LogLevelDebug
+}
+
+func (l printfLogger) Log(_ context.Context, level LogLevel, message string, fields map[string]interface{}) {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s] pritunl: %s", level, message)
+	for _, key := range keys {
+		fmt.Fprintf(&b, " %s=%v", key, fields[key])
+	}
+	l.logger.Print(b.String())
+}
+
+const redacted = "***"
+
+var redactedHeaders = []string{"Auth-Token", "Auth-Signature"}
+
+// isSecretKey reports if the JSON value of the key must be kept out of the logs, e.g. pin,
+// otp_secret, auth_secret, sso_okta_token, sso_google_key or email_password
+func isSecretKey(key string) bool {
+	return key == "pin" ||
+		strings.HasSuffix(key, "secret") ||
+		strings.HasSuffix(key, "token") ||
+		strings.HasSuffix(key, "key") ||
+		strings.HasSuffix(key, "password")
+}
+
+// redactHeaders returns the headers for the logs without the credentials
+func redactHeaders(header http.Header) map[string]string {
+	result := make(map[string]string, len(header))
+	for name := range header {
+		result[name] = header.Get(name)
+	}
+
+	for _, name := range redactedHeaders {
+		if _, ok := result[name]; ok {
+			result[name] = redacted
+		}
+	}
+
+	return result
+}
+
+// redactBody returns the body for the logs without the secret values. The user profiles
+// served under /key contain the private keys and are left out.
+func redactBody(path string, body []byte) string {
+	if strings.Contains(path, "/key/") {
+		return "[profile redacted]"
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return string(body)
+	}
+
+	redactedBody, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return string(body)
+	}
+
+	return string(redactedBody)
+}
+
+func redactValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, item := range value {
+			if isSecretKey(key) {
+				value[key] = redacted
+			} else {
+				value[key] = redactValue(item)
+			}
+		}
+	case []interface{}:
+		for i, item := range value {
+			value[i] = redactValue(item)
+		}
+	}
+
+	return value
+}
+
+// withBody returns a copy of the fields with the body, the fields already logged are left
+// untouched for the loggers keeping them
+func withBody(fields map[string]interface{}, body string) map[string]interface{} {
+	result := make(map[string]interface{}, len(fields)+1)
+	for key, value := range fields {
+		result[key] = value
+	}
+	result["body"] = body
+
+	return result
+}
+
+func (t *transport) debug(req *http.Request, message string, fields map[string]interface{}) {
+	if t.logger != nil {
+		t.logger.Log(req.Context(), LogLevelDebug, message, fields)
+	}
+}
+
+// logRequest logs the signed request, with its body at the TRACE level
+func (t *transport) logRequest(req *http.Request) {
+	fields := map[string]interface{}{
+		"method":   req.Method,
+		"path":     req.URL.Path,
+		"endpoint": req.URL.Scheme + "://" + req.URL.Host,
+		"headers":  redactHeaders(req.Header),
+	}
+	t.logger.Log(req.Context(), LogLevelDebug, "Sending HTTP request", fields)
+
+	// the body is read from a copy, requests without GetBody are logged without it
+	if !t.logBodies || req.GetBody == nil || !t.logger.Enabled(LogLevelTrace) {
+		return
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	content, err := io.ReadAll(body)
+	if err != nil || len(content) == 0 {
+		return
+	}
+
+	t.logger.Log(req.Context(), LogLevelTrace, "HTTP request body", withBody(fields, redactBody(req.URL.Path, content)))
+}
+
+// logResponse logs the response, with its body at the TRACE level. The body is read and
+// replaced by a copy then.
+func (t *transport) logResponse(req *http.Request, resp *http.Response, latency time.Duration) (*http.Response, error) {
+	fields := map[string]interface{}{
+		"method":     req.Method,
+		"path":       req.URL.Path,
+		"endpoint":   req.URL.Scheme + "://" + req.URL.Host,
+		"status":     resp.StatusCode,
+		"latency_ms": latency.Milliseconds(),
+	}
+	t.logger.Log(req.Context(), LogLevelDebug, "Received HTTP response", fields)
+
+	if !t.logBodies || !t.logger.Enabled(LogLevelTrace) {
+		return resp, nil
+	}
+
+	content, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(content))
+
+	t.logger.Log(req.Context(), LogLevelTrace, "HTTP response body", withBody(fields, redactBody(req.URL.Path, content)))
+
+	return resp, nil
+}
@@ -0,0 +1,68 @@
+package pritunl
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// addForceSendFields adds the attributes listed in forceSendFields to an already encoded model.
+//
+// Most model fields are tagged with omitempty, so zero values such as false, 0, "" or an
+// empty list never reach the Pritunl API. Listing the JSON name of a field in
+// forceSendFields sends its value even when it is empty, which is how a resource clears
+// an attribute. An empty list is sent as [] rather than null.
+func addForceSendFields(data []byte, model interface{}, forceSendFields []string) ([]byte, error) {
+	if len(forceSendFields) == 0 {
+		return data, nil
+	}
+
+	var attributes map[string]json.RawMessage
+	err := json.Unmarshal(data, &attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	value := reflect.Indirect(reflect.ValueOf(model))
+	fields := jsonFields(value)
+
+	for _, name := range forceSendFields {
+		if _, ok := attributes[name]; ok {
+			continue
+		}
+
+		field, ok := fields[name]
+		if !ok {
+			continue
+		}
+
+		if field.Kind() == reflect.Slice && field.IsNil() {
+			field = reflect.MakeSlice(field.Type(), 0, 0)
+		}
+
+		raw, err := json.Marshal(field.Interface())
+		if err != nil {
+			return nil, err
+		}
+		attributes[name] = raw
+	}
+
+	return json.Marshal(attributes)
+}
+
+// jsonFields maps JSON attribute names to the struct fields holding them
+func jsonFields(value reflect.Value) map[string]reflect.Value {
+	fields := make(map[string]reflect.Value)
+
+	for i := 0; i < value.NumField(); i++ {
+		tag := value.Type().Field(i).Tag.Get("json")
+		name := strings.Split(tag, ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+
+		fields[name] = value.Field(i)
+	}
+
+	return fields
+}
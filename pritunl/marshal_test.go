@@ -0,0 +1,173 @@
+package pritunl
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func unmarshalAttributes(t *testing.T, data []byte) map[string]interface{} {
+	t.Helper()
+
+	var attributes map[string]interface{}
+	if err := json.Unmarshal(data, &attributes); err != nil {
+		t.Fatalf("failed to unmarshal %s: %s", data, err)
+	}
+
+	return attributes
+}
+
+func TestServerMarshalJSON(t *testing.T) {
+	t.Run("omits zero values by default", func(t *testing.T) {
+		server := &Server{Name: "test", MaxDevices: 0, Debug: false}
+
+		data, err := json.Marshal(server)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		attributes := unmarshalAttributes(t, data)
+		for _, name := range []string{"max_devices", "debug", "dns_servers"} {
+			if _, ok := attributes[name]; ok {
+				t.Errorf("expected %s to be omitted in %s", name, data)
+			}
+		}
+		if attributes["mss_fix"] != "0" {
+			t.Errorf("expected mss_fix to be sent as a string, got %v", attributes["mss_fix"])
+		}
+	})
+
+	t.Run("sends forced zero values", func(t *testing.T) {
+		server := &Server{
+			Name:            "test",
+			Debug:           false,
+			MaxDevices:      0,
+			PingTimeout:     0,
+			DnsServers:      nil,
+			Groups:          []string{},
+			PreConnectMsg:   "",
+			ForceSendFields: []string{"debug", "max_devices", "ping_timeout", "dns_servers", "groups", "pre_connect_msg"},
+		}
+
+		data, err := json.Marshal(server)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		attributes := unmarshalAttributes(t, data)
+		expected := map[string]interface{}{
+			"name":            "test",
+			"debug":           false,
+			"max_devices":     float64(0),
+			"ping_timeout":    float64(0),
+			"pre_connect_msg": "",
+		}
+		for name, value := range expected {
+			if attributes[name] != value {
+				t.Errorf("expected %s = %v, got %v in %s", name, value, attributes[name], data)
+			}
+		}
+
+		for _, name := range []string{"dns_servers", "groups"} {
+			list, ok := attributes[name].([]interface{})
+			if !ok || len(list) != 0 {
+				t.Errorf("expected %s to be an empty list, got %v in %s", name, attributes[name], data)
+			}
+		}
+	})
+
+	t.Run("does not send the force send fields list itself", func(t *testing.T) {
+		data, err := json.Marshal(&Server{Name: "test", ForceSendFields: []string{"debug"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, ok := unmarshalAttributes(t, data)["ForceSendFields"]; ok {
+			t.Errorf("unexpected ForceSendFields in %s", data)
+		}
+	})
+
+	t.Run("keeps non-zero values", func(t *testing.T) {
+		data, err := json.Marshal(&Server{Name: "test", MaxDevices: 3, MssFix: 1400, ForceSendFields: []string{"max_devices"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		attributes := unmarshalAttributes(t, data)
+		if attributes["max_devices"] != float64(3) {
+			t.Errorf("expected max_devices = 3, got %v", attributes["max_devices"])
+		}
+		if attributes["mss_fix"] != "1400" {
+			t.Errorf("expected mss_fix = \"1400\", got %v", attributes["mss_fix"])
+		}
+	})
+}
+
+func TestUserMarshalJSON(t *testing.T) {
+	t.Run("omits zero values by default", func(t *testing.T) {
+		data, err := json.Marshal(&User{Name: "test", Organization: "org"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		attributes := unmarshalAttributes(t, data)
+		for _, name := range []string{"disabled", "groups", "email", "pin"} {
+			if _, ok := attributes[name]; ok {
+				t.Errorf("expected %s to be omitted in %s", name, data)
+			}
+		}
+	})
+
+	t.Run("sends forced zero values", func(t *testing.T) {
+		user := &User{
+			Name:            "test",
+			Organization:    "org",
+			Disabled:        false,
+			Groups:          []string{},
+			Email:           "",
+			BypassSecondary: false,
+			ForceSendFields: []string{"disabled", "groups", "email", "bypass_secondary"},
+		}
+
+		data, err := json.Marshal(user)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		attributes := unmarshalAttributes(t, data)
+		if attributes["disabled"] != false || attributes["bypass_secondary"] != false || attributes["email"] != "" {
+			t.Errorf("expected forced zero values in %s", data)
+		}
+		if groups, ok := attributes["groups"].([]interface{}); !ok || len(groups) != 0 {
+			t.Errorf("expected groups to be an empty list in %s", data)
+		}
+	})
+
+	t.Run("sends the pin secret", func(t *testing.T) {
+		data, err := json.Marshal(&User{Name: "test", Pin: &Pin{Secret: "123456"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if pin := unmarshalAttributes(t, data)["pin"]; pin != "123456" {
+			t.Errorf("expected pin = 123456, got %v", pin)
+		}
+	})
+
+	t.Run("keeps or clears the pin", func(t *testing.T) {
+		data, err := json.Marshal(&User{Name: "test", Pin: &Pin{IsSet: true}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pin := unmarshalAttributes(t, data)["pin"]; pin != true {
+			t.Errorf("expected the pin of a user read from the API to be kept, got %v", pin)
+		}
+
+		data, err = json.Marshal(&User{Name: "test", Pin: &Pin{}, ForceSendFields: []string{"pin"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pin, ok := unmarshalAttributes(t, data)["pin"]; !ok || pin != nil {
+			t.Errorf("expected pin = null to clear the pin, got %s", data)
+		}
+	})
+}
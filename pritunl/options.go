@@ -0,0 +1,113 @@
+package pritunl
+
+import (
+	"crypto/tls"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Option configures a client created with NewClient
+type Option func(*options)
+
+type options struct {
+	apiToken            string
+	apiSecret           string
+	credentialsFunc     CredentialsFunc
+	insecure            bool
+	tlsConfig           *tls.Config
+	timeout             time.Duration
+	userAgent           string
+	underlyingTransport http.RoundTripper
+	failoverUrls        []string
+	logger              Logger
+	logBodies           bool
+}
+
+// WithCredentials sets the API token and secret of the administrator the requests are signed for
+func WithCredentials(apiToken, apiSecret string) Option {
+	return func(o *options) {
+		o.apiToken = apiToken
+		o.apiSecret = apiSecret
+	}
+}
+
+// WithCredentialsFunc gets the API token and secret from fn instead, e.g. to read them from
+// files or a secrets manager. It takes precedence over WithCredentials.
+func WithCredentialsFunc(fn CredentialsFunc) Option {
+	return func(o *options) {
+		o.credentialsFunc = fn
+	}
+}
+
+// WithInsecure skips the verification of the Pritunl TLS certificate
+func WithInsecure(insecure bool) Option {
+	return func(o *options) {
+		o.insecure = insecure
+	}
+}
+
+// WithTLSConfig sets the TLS configuration of the default transport, e.g. to trust a private CA
+// or to present a client certificate. WithInsecure still skips the verification when set.
+func WithTLSConfig(tlsConfig *tls.Config) Option {
+	return func(o *options) {
+		o.tlsConfig = tlsConfig
+	}
+}
+
+// WithTimeout limits the time a request can take, including reading the response. There is no limit by default.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.timeout = timeout
+	}
+}
+
+// WithUserAgent sets the User-Agent header, pritunl-go is appended to it
+func WithUserAgent(userAgent string) Option {
+	return func(o *options) {
+		o.userAgent = userAgent
+	}
+}
+
+// WithTransport sends the signed requests through the transport instead of the default
+// one, e.g. to add tracing or to test the client. WithInsecure and WithTLSConfig have no effect then.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(o *options) {
+		o.underlyingTransport = transport
+	}
+}
+
+// WithFailoverUrls adds the API URLs of the other hosts of a Pritunl cluster. A GET or HEAD
+// request failing with a connection error or a 502, 503 or 504 response is sent to the next URL,
+// the other requests only when the connection couldn't be opened. The URL that served the last
+// request is tried first.
+func WithFailoverUrls(baseUrls ...string) Option {
+	return func(o *options) {
+		o.failoverUrls = append(o.failoverUrls, baseUrls...)
+	}
+}
+
+// WithLogger logs the requests, the responses and the failovers at the [DEBUG] level.
+// Nothing is logged by default.
+func WithLogger(logger *log.Logger) Option {
+	return func(o *options) {
+		o.logger = printfLogger{logger: logger}
+	}
+}
+
+// WithStructuredLogger logs the requests to logger instead. The method, path, API URL, status
+// and latency are logged at the DEBUG level and the bodies at the TRACE level, with the
+// credentials, PINs, OTP and other secrets and the user profiles redacted.
+func WithStructuredLogger(logger Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// WithBodyLogging enables or disables the logging of the request and response bodies, they are
+// logged by default when the logger enables LogLevelTrace
+func WithBodyLogging(enabled bool) Option {
+	return func(o *options) {
+		o.logBodies = enabled
+	}
+}
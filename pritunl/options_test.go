@@ -0,0 +1,94 @@
+package pritunl
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// recordingTransport records the requests and responds with an empty list
+type recordingTransport struct {
+	requests []*http.Request
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests = append(t.requests, req)
+
+	recorder := httptest.NewRecorder()
+	recorder.WriteString("[]")
+	return recorder.Result(), nil
+}
+
+func TestNewClientOptions(t *testing.T) {
+	recorder := &recordingTransport{}
+
+	apiClient := NewClient("https://vpn.example.com/api",
+		WithCredentials("token", "secret"),
+		WithUserAgent("audit/1.0"),
+		WithTimeout(time.Minute),
+		WithTransport(recorder),
+	)
+
+	if _, err := apiClient.GetOrganizations(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(recorder.requests) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(recorder.requests))
+	}
+	req := recorder.requests[0]
+
+	if req.URL.String() != "https://vpn.example.com/api/organization" {
+		t.Errorf("unexpected URL %s", req.URL)
+	}
+	if req.Header.Get("Auth-Token") != "token" {
+		t.Errorf("expected the token header, got %q", req.Header.Get("Auth-Token"))
+	}
+	for _, header := range []string{"Auth-Timestamp", "Auth-Nonce", "Auth-Signature"} {
+		if req.Header.Get(header) == "" {
+			t.Errorf("expected the %s header", header)
+		}
+	}
+	if userAgent := req.Header.Get("User-Agent"); userAgent != "audit/1.0 pritunl-go" {
+		t.Errorf("unexpected User-Agent %q", userAgent)
+	}
+
+	if timeout := apiClient.(*client).httpClient.Timeout; timeout != time.Minute {
+		t.Errorf("expected a 1m timeout, got %s", timeout)
+	}
+}
+
+func TestNewClientDefaults(t *testing.T) {
+	apiClient := NewClient("https://vpn.example.com", WithInsecure(true))
+
+	httpClient := apiClient.(*client).httpClient
+	underlying := httpClient.Transport.(*transport).underlyingTransport.(*http.Transport)
+	if !underlying.TLSClientConfig.InsecureSkipVerify {
+		t.Error("expected the TLS verification to be skipped")
+	}
+	if httpClient.Timeout != 0 {
+		t.Errorf("expected no timeout, got %s", httpClient.Timeout)
+	}
+	if userAgent := httpClient.Transport.(*transport).userAgent; userAgent != "pritunl-go" {
+		t.Errorf("unexpected User-Agent %q", userAgent)
+	}
+}
+
+func TestNewClientTLSConfig(t *testing.T) {
+	tlsConfig := &tls.Config{ServerName: "vpn.internal", MinVersion: tls.VersionTLS13}
+	apiClient := NewClient("https://vpn.example.com", WithTLSConfig(tlsConfig), WithInsecure(true))
+
+	httpClient := apiClient.(*client).httpClient
+	underlying := httpClient.Transport.(*transport).underlyingTransport.(*http.Transport)
+	if underlying.TLSClientConfig.ServerName != "vpn.internal" || underlying.TLSClientConfig.MinVersion != tls.VersionTLS13 {
+		t.Errorf("expected the TLS configuration to be used, got %+v", underlying.TLSClientConfig)
+	}
+	if !underlying.TLSClientConfig.InsecureSkipVerify {
+		t.Error("expected the TLS verification to be skipped")
+	}
+	if tlsConfig.InsecureSkipVerify {
+		t.Error("expected the TLS configuration not to be modified")
+	}
+}
@@ -0,0 +1,46 @@
+package pritunl
+
+import (
+	"encoding/json"
+)
+
+type Organization struct {
+	ID         string `json:"id,omitempty"`
+	Name       string `json:"name"`
+	AuthApi    bool   `json:"auth_api"`
+	AuthToken  string `json:"auth_token,omitempty"`
+	AuthSecret string `json:"auth_secret,omitempty"`
+	UserCount  int    `json:"user_count,omitempty"`
+
+	// Ask the API to generate a new auth token and secret on update
+	RegenerateAuthCredentials bool `json:"-"`
+}
+
+// MarshalJSON encodes an organization for update requests.
+//
+// The API treats any true value of auth_token and auth_secret as a request to regenerate
+// them, so the values read from the API are never sent back.
+func (o *Organization) MarshalJSON() ([]byte, error) {
+	type Alias Organization
+	data, err := json.Marshal((*Alias)(o))
+	if err != nil {
+		return nil, err
+	}
+
+	var attributes map[string]interface{}
+	err = json.Unmarshal(data, &attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range []string{"auth_token", "auth_secret", "user_count"} {
+		delete(attributes, name)
+	}
+
+	if o.RegenerateAuthCredentials {
+		attributes["auth_token"] = true
+		attributes["auth_secret"] = true
+	}
+
+	return json.Marshal(attributes)
+}
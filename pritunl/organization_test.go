@@ -0,0 +1,42 @@
+package pritunl
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestOrganizationMarshalJSON(t *testing.T) {
+	organization := &Organization{
+		Name:       "org",
+		AuthToken:  "token",
+		AuthSecret: "secret",
+		UserCount:  3,
+	}
+
+	data, err := json.Marshal(organization)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attributes := unmarshalAttributes(t, data)
+	for _, name := range []string{"auth_token", "auth_secret", "user_count"} {
+		if _, ok := attributes[name]; ok {
+			t.Errorf("expected %s to be omitted in %s", name, data)
+		}
+	}
+	if attributes["auth_api"] != false {
+		t.Errorf("expected auth_api to be sent, got %v", attributes["auth_api"])
+	}
+
+	organization.RegenerateAuthCredentials = true
+
+	data, err = json.Marshal(organization)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attributes = unmarshalAttributes(t, data)
+	if attributes["auth_token"] != true || attributes["auth_secret"] != true {
+		t.Errorf("expected auth_token and auth_secret to be true in %s", data)
+	}
+}
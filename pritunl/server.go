@@ -0,0 +1,223 @@
+package pritunl
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+)
+
+const (
+	ServerStatusOnline  = "online"
+	ServerStatusOffline = "offline"
+
+	ServerNetworkModeTunnel = "tunnel"
+	ServerNetworkModeBridge = "bridge"
+)
+
+type Server struct {
+	ID               string   `json:"id,omitempty"`
+	Name             string   `json:"name"`
+	Protocol         string   `json:"protocol,omitempty"`
+	Cipher           string   `json:"cipher,omitempty"`
+	Hash             string   `json:"hash,omitempty"`
+	Port             int      `json:"port,omitempty"`
+	Network          string   `json:"network,omitempty"`
+	WG               bool     `json:"wg,omitempty"`
+	PortWG           int      `json:"port_wg,omitempty"`
+	NetworkWG        string   `json:"network_wg,omitempty"`
+	NetworkMode      string   `json:"network_mode,omitempty"`
+	NetworkStart     string   `json:"network_start,omitempty"`
+	NetworkEnd       string   `json:"network_end,omitempty"`
+	RestrictRoutes   bool     `json:"restrict_routes,omitempty"`
+	IPv6             bool     `json:"ipv6,omitempty"`
+	IPv6Firewall     bool     `json:"ipv6_firewall,omitempty"`
+	BindAddress      string   `json:"bind_address,omitempty"`
+	DhParamBits      int      `json:"dh_param_bits,omitempty"`
+	Groups           []string `json:"groups,omitempty"`
+	MultiDevice      bool     `json:"multi_device,omitempty"`
+	DnsServers       []string `json:"dns_servers,omitempty"`
+	SearchDomain     string   `json:"search_domain,omitempty"`
+	InterClient      bool     `json:"inter_client,omitempty"`
+	PingInterval     int      `json:"ping_interval,omitempty"`
+	PingTimeout      int      `json:"ping_timeout,omitempty"`
+	LinkPingInterval int      `json:"link_ping_interval,omitempty"`
+	LinkPingTimeout  int      `json:"link_ping_timeout,omitempty"`
+	InactiveTimeout  int      `json:"inactive_timeout,omitempty"`
+	SessionTimeout   int      `json:"session_timeout,omitempty"`
+	AllowedDevices   string   `json:"allowed_devices,omitempty"`
+	MaxClients       int      `json:"max_clients,omitempty"`
+	MaxDevices       int      `json:"max_devices,omitempty"`
+	ReplicaCount     int      `json:"replica_count,omitempty"`
+	VxLan            bool     `json:"vxlan,omitempty"`
+	DnsMapping       bool     `json:"dns_mapping,omitempty"`
+	PreConnectMsg    string   `json:"pre_connect_msg,omitempty"`
+	SsoAuth          bool     `json:"sso_auth,omitempty"`
+	OtpAuth          bool     `json:"otp_auth,omitempty"`
+	DeviceAuth       bool     `json:"device_auth,omitempty"`
+	DynamicFirewall  bool     `json:"dynamic_firewall,omitempty"`
+	MssFix           int      `json:"mss_fix,omitempty"`
+	LzoCompression   bool     `json:"lzo_compression,omitempty"`
+	BlockOutsideDns  bool     `json:"block_outside_dns,omitempty"`
+	JumboFrames      bool     `json:"jumbo_frames,omitempty"`
+	RouteDns         bool     `json:"route_dns,omitempty"`
+	Debug            bool     `json:"debug,omitempty"`
+	Status           string   `json:"status,omitempty"`
+
+	// JSON names of the attributes sent even when they hold a zero value, e.g. to clear them on update
+	ForceSendFields []string `json:"-"`
+}
+
+// ServerOutput holds the log lines a server or its links wrote on the Pritunl hosts
+type ServerOutput struct {
+	ID     string   `json:"id"`
+	Output []string `json:"output"`
+}
+
+func (s *Server) MarshalJSON() ([]byte, error) {
+	type Alias Server
+	data, err := json.Marshal(&struct {
+		// Pritunl API expects input mss_fix value as a string, but returns as an int
+		MssFix string `json:"mss_fix"`
+		*Alias
+	}{
+		MssFix: strconv.Itoa(s.MssFix),
+		Alias:  (*Alias)(s),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return addForceSendFields(data, s, s.ForceSendFields)
+}
+
+// ServerCreateRequest holds the attributes accepted when creating a new server
+type ServerCreateRequest struct {
+	Name             string
+	Protocol         string
+	Cipher           string
+	Hash             string
+	Port             int
+	Network          string
+	PortWG           int
+	NetworkWG        string
+	NetworkMode      string
+	NetworkStart     string
+	NetworkEnd       string
+	RestrictRoutes   bool
+	IPv6             bool
+	IPv6Firewall     bool
+	BindAddress      string
+	DhParamBits      int
+	Groups           []string
+	MultiDevice      bool
+	DnsServers       []string
+	SearchDomain     string
+	InterClient      bool
+	PingInterval     int
+	PingTimeout      int
+	LinkPingInterval int
+	LinkPingTimeout  int
+	InactiveTimeout  int
+	SessionTimeout   int
+	AllowedDevices   string
+	MaxClients       int
+	MaxDevices       int
+	ReplicaCount     int
+	VxLan            bool
+	DnsMapping       bool
+	PreConnectMsg    string
+	SsoAuth          bool
+	OtpAuth          bool
+	DeviceAuth       bool
+	DynamicFirewall  bool
+	MssFix           int
+	LzoCompression   bool
+	BlockOutsideDns  bool
+	JumboFrames      bool
+	RouteDns         bool
+	Debug            bool
+
+	// JSON names of the attributes sent even when they hold a zero value
+	ForceSendFields []string
+}
+
+// Server returns the server model sent to the Pritunl API on creation
+func (r ServerCreateRequest) Server() *Server {
+	return &Server{
+		Name:             r.Name,
+		Protocol:         r.Protocol,
+		Cipher:           r.Cipher,
+		Hash:             r.Hash,
+		Port:             r.Port,
+		Network:          r.Network,
+		WG:               r.NetworkWG != "" && r.PortWG > 0,
+		PortWG:           r.PortWG,
+		NetworkWG:        r.NetworkWG,
+		NetworkMode:      r.NetworkMode,
+		NetworkStart:     r.NetworkStart,
+		NetworkEnd:       r.NetworkEnd,
+		RestrictRoutes:   r.RestrictRoutes,
+		IPv6:             r.IPv6,
+		IPv6Firewall:     r.IPv6Firewall,
+		BindAddress:      r.BindAddress,
+		DhParamBits:      r.DhParamBits,
+		Groups:           r.Groups,
+		MultiDevice:      r.MultiDevice,
+		DnsServers:       r.DnsServers,
+		SearchDomain:     r.SearchDomain,
+		InterClient:      r.InterClient,
+		PingInterval:     r.PingInterval,
+		PingTimeout:      r.PingTimeout,
+		LinkPingInterval: r.LinkPingInterval,
+		LinkPingTimeout:  r.LinkPingTimeout,
+		InactiveTimeout:  r.InactiveTimeout,
+		SessionTimeout:   r.SessionTimeout,
+		AllowedDevices:   r.AllowedDevices,
+		MaxClients:       r.MaxClients,
+		MaxDevices:       r.MaxDevices,
+		ReplicaCount:     r.ReplicaCount,
+		VxLan:            r.VxLan,
+		DnsMapping:       r.DnsMapping,
+		PreConnectMsg:    r.PreConnectMsg,
+		SsoAuth:          r.SsoAuth,
+		OtpAuth:          r.OtpAuth,
+		DeviceAuth:       r.DeviceAuth,
+		DynamicFirewall:  r.DynamicFirewall,
+		MssFix:           r.MssFix,
+		LzoCompression:   r.LzoCompression,
+		BlockOutsideDns:  r.BlockOutsideDns,
+		JumboFrames:      r.JumboFrames,
+		RouteDns:         r.RouteDns,
+		Debug:            r.Debug,
+		ForceSendFields:  r.ForceSendFields,
+	}
+}
+
+// ValidateServer checks the server attributes that depend on each other before they are sent to the Pritunl API
+func ValidateServer(server *Server) error {
+	var errs []error
+
+	if server.NetworkMode == ServerNetworkModeBridge {
+		if server.NetworkStart == "" || server.NetworkEnd == "" {
+			errs = append(errs, fmt.Errorf("the attribute network_mode = %s requires network_start and network_end attributes", ServerNetworkModeBridge))
+		} else if _, network, err := net.ParseCIDR(server.Network); err == nil {
+			networkStart := net.ParseIP(server.NetworkStart)
+			networkEnd := net.ParseIP(server.NetworkEnd)
+
+			if networkStart == nil || !network.Contains(networkStart) {
+				errs = append(errs, fmt.Errorf("network_start %s is outside of the server network %s", server.NetworkStart, network))
+			}
+			if networkEnd == nil || !network.Contains(networkEnd) {
+				errs = append(errs, fmt.Errorf("network_end %s is outside of the server network %s", server.NetworkEnd, network))
+			}
+			if len(errs) == 0 && bytes.Compare(networkStart.To16(), networkEnd.To16()) > 0 {
+				errs = append(errs, fmt.Errorf("network_start %s must not be greater than network_end %s", networkStart, networkEnd))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
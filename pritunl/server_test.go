@@ -0,0 +1,100 @@
+package pritunl
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateServer(t *testing.T) {
+	testCases := []struct {
+		name        string
+		server      Server
+		expectedErr string
+	}{
+		{
+			name:   "tunnel mode without a range",
+			server: Server{Network: "10.0.0.0/24", NetworkMode: ServerNetworkModeTunnel},
+		},
+		{
+			name:   "bridge mode with a range inside the network",
+			server: Server{Network: "10.0.0.0/24", NetworkMode: ServerNetworkModeBridge, NetworkStart: "10.0.0.10", NetworkEnd: "10.0.0.100"},
+		},
+		{
+			name:        "bridge mode without network_start",
+			server:      Server{Network: "10.0.0.0/24", NetworkMode: ServerNetworkModeBridge, NetworkEnd: "10.0.0.100"},
+			expectedErr: "requires network_start and network_end attributes",
+		},
+		{
+			name:        "bridge mode without network_end",
+			server:      Server{Network: "10.0.0.0/24", NetworkMode: ServerNetworkModeBridge, NetworkStart: "10.0.0.10"},
+			expectedErr: "requires network_start and network_end attributes",
+		},
+		{
+			name:        "bridge mode with network_start outside the network",
+			server:      Server{Network: "10.0.0.0/24", NetworkMode: ServerNetworkModeBridge, NetworkStart: "10.0.1.10", NetworkEnd: "10.0.0.100"},
+			expectedErr: "network_start 10.0.1.10 is outside of the server network 10.0.0.0/24",
+		},
+		{
+			name:        "bridge mode with network_end outside the network",
+			server:      Server{Network: "10.0.0.0/24", NetworkMode: ServerNetworkModeBridge, NetworkStart: "10.0.0.10", NetworkEnd: "10.0.1.100"},
+			expectedErr: "network_end 10.0.1.100 is outside of the server network 10.0.0.0/24",
+		},
+		{
+			name:        "bridge mode with network_start greater than network_end",
+			server:      Server{Network: "10.0.0.0/24", NetworkMode: ServerNetworkModeBridge, NetworkStart: "10.0.0.100", NetworkEnd: "10.0.0.10"},
+			expectedErr: "network_start 10.0.0.100 must not be greater than network_end 10.0.0.10",
+		},
+		{
+			name:   "bridge mode with a single address range",
+			server: Server{Network: "10.0.0.0/24", NetworkMode: ServerNetworkModeBridge, NetworkStart: "10.0.0.10", NetworkEnd: "10.0.0.10"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateServer(&tc.server)
+
+			if tc.expectedErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("expected an error containing %q, got nil", tc.expectedErr)
+			}
+			if !strings.Contains(err.Error(), tc.expectedErr) {
+				t.Errorf("expected an error containing %q, got %q", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestServerCreateRequestServer(t *testing.T) {
+	t.Run("enables WireGuard when network_wg and port_wg are set", func(t *testing.T) {
+		server := ServerCreateRequest{Name: "test", NetworkWG: "10.1.0.0/24", PortWG: 51820}.Server()
+		if !server.WG {
+			t.Errorf("expected WireGuard to be enabled")
+		}
+	})
+
+	t.Run("passes the force send fields to the server", func(t *testing.T) {
+		server := ServerCreateRequest{Name: "test", IPv6Firewall: false, ForceSendFields: []string{"ipv6_firewall"}}.Server()
+
+		data, err := server.MarshalJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), `"ipv6_firewall":false`) {
+			t.Errorf("expected ipv6_firewall to be sent, got %s", data)
+		}
+	})
+
+	t.Run("keeps WireGuard disabled without port_wg", func(t *testing.T) {
+		server := ServerCreateRequest{Name: "test", NetworkWG: "10.1.0.0/24"}.Server()
+		if server.WG {
+			t.Errorf("expected WireGuard to be disabled")
+		}
+	})
+}
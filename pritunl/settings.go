@@ -0,0 +1,73 @@
+package pritunl
+
+import (
+	"encoding/json"
+)
+
+type Settings struct {
+	Theme           string          `json:"theme,omitempty"`
+	PublicAddress   string          `json:"public_address,omitempty"`
+	PublicAddress6  string          `json:"public_address6,omitempty"`
+	RoutedSubnet6   string          `json:"routed_subnet6,omitempty"`
+	RoutedSubnet6WG string          `json:"routed_subnet6_wg,omitempty"`
+	ReverseProxy    bool            `json:"reverse_proxy,omitempty"`
+	ServerPort      int             `json:"server_port,omitempty"`
+	AcmeDomain      string          `json:"acme_domain,omitempty"`
+	RestrictImport  bool            `json:"restrict_import,omitempty"`
+	ClientReconnect bool            `json:"client_reconnect,omitempty"`
+	PinMode         string          `json:"pin_mode,omitempty"`
+	EmailFrom       string          `json:"email_from,omitempty"`
+	EmailServer     string          `json:"email_server,omitempty"`
+	EmailUsername   string          `json:"email_username,omitempty"`
+	EmailPassword   WriteOnlyString `json:"email_password,omitempty"`
+
+	Sso                 string   `json:"sso,omitempty"`
+	SsoMatch            []string `json:"sso_match,omitempty"`
+	SsoOrg              string   `json:"sso_org,omitempty"`
+	SsoCache            bool     `json:"sso_cache,omitempty"`
+	SsoClientCache      bool     `json:"sso_client_cache,omitempty"`
+	SsoGoogleEmail      string   `json:"sso_google_email,omitempty"`
+	SsoGoogleKey        string   `json:"sso_google_key,omitempty"`
+	SsoAzureDirectoryId string   `json:"sso_azure_directory_id,omitempty"`
+	SsoAzureAppId       string   `json:"sso_azure_app_id,omitempty"`
+	SsoAzureAppSecret   string   `json:"sso_azure_app_secret,omitempty"`
+	SsoSamlUrl          string   `json:"sso_saml_url,omitempty"`
+	SsoSamlIssuerUrl    string   `json:"sso_saml_issuer_url,omitempty"`
+	SsoSamlCert         string   `json:"sso_saml_cert,omitempty"`
+	SsoOktaAppId        string   `json:"sso_okta_app_id,omitempty"`
+	SsoOktaToken        string   `json:"sso_okta_token,omitempty"`
+	SsoOktaMode         string   `json:"sso_okta_mode,omitempty"`
+	SsoOneloginAppId    string   `json:"sso_onelogin_app_id,omitempty"`
+	SsoOneloginId       string   `json:"sso_onelogin_id,omitempty"`
+	SsoOneloginSecret   string   `json:"sso_onelogin_secret,omitempty"`
+	SsoOneloginMode     string   `json:"sso_onelogin_mode,omitempty"`
+	SsoRadiusHost       string   `json:"sso_radius_host,omitempty"`
+	SsoRadiusSecret     string   `json:"sso_radius_secret,omitempty"`
+	SsoDuoHost          string   `json:"sso_duo_host,omitempty"`
+	SsoDuoToken         string   `json:"sso_duo_token,omitempty"`
+	SsoDuoSecret        string   `json:"sso_duo_secret,omitempty"`
+	SsoDuoMode          string   `json:"sso_duo_mode,omitempty"`
+	SsoYubicoClient     string   `json:"sso_yubico_client,omitempty"`
+	SsoYubicoSecret     string   `json:"sso_yubico_secret,omitempty"`
+
+	// JSON names of the attributes sent even when they hold a zero value, e.g. to clear them on update
+	ForceSendFields []string `json:"-"`
+}
+
+func (s *Settings) MarshalJSON() ([]byte, error) {
+	type Alias Settings
+	data, err := json.Marshal((*Alias)(s))
+	if err != nil {
+		return nil, err
+	}
+
+	return addForceSendFields(data, s, s.ForceSendFields)
+}
+
+// WriteOnlyString is a secret the API accepts but never returns, whatever the API
+// responds with in its place is ignored and the decoded value stays empty.
+type WriteOnlyString string
+
+func (s *WriteOnlyString) UnmarshalJSON([]byte) error {
+	return nil
+}
@@ -0,0 +1,43 @@
+package pritunl
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSettingsMarshalJSON(t *testing.T) {
+	settings := &Settings{
+		RestrictImport:  false,
+		EmailPassword:   "secret",
+		ForceSendFields: []string{"restrict_import", "email_password"},
+	}
+
+	data, err := json.Marshal(settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attributes := unmarshalAttributes(t, data)
+	if len(attributes) != 2 {
+		t.Errorf("expected only the forced attributes to be sent, got %s", data)
+	}
+	if attributes["restrict_import"] != false {
+		t.Errorf("expected restrict_import to be false, got %v", attributes["restrict_import"])
+	}
+	if attributes["email_password"] != "secret" {
+		t.Errorf("expected email_password to be sent, got %v", attributes["email_password"])
+	}
+}
+
+func TestSettingsUnmarshalWriteOnly(t *testing.T) {
+	for _, body := range []string{`{"email_password": true}`, `{"email_password": "secret"}`, `{"email_password": null}`} {
+		var settings Settings
+		if err := json.Unmarshal([]byte(body), &settings); err != nil {
+			t.Fatalf("failed to unmarshal %s: %s", body, err)
+		}
+
+		if settings.EmailPassword != "" {
+			t.Errorf("expected email_password to be ignored in %s, got %q", body, settings.EmailPassword)
+		}
+	}
+}
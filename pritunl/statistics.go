@@ -0,0 +1,43 @@
+package pritunl
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Periods supported by the Pritunl usage and bandwidth graphs
+var StatisticsPeriods = []string{"1m", "5m", "30m", "2h", "1d"}
+
+// StatisticsPoint is a single sample of a graph, the API encodes it as a [timestamp, value] pair
+type StatisticsPoint struct {
+	Timestamp int64
+	Value     float64
+}
+
+func (p *StatisticsPoint) UnmarshalJSON(data []byte) error {
+	var pair []float64
+	if err := json.Unmarshal(data, &pair); err != nil {
+		return err
+	}
+
+	if len(pair) != 2 {
+		return fmt.Errorf("expected a [timestamp, value] pair, got %s", data)
+	}
+
+	p.Timestamp = int64(pair[0])
+	p.Value = pair[1]
+
+	return nil
+}
+
+type HostUsage struct {
+	Cpu []StatisticsPoint `json:"cpu"`
+	Mem []StatisticsPoint `json:"mem"`
+}
+
+type ServerBandwidth struct {
+	Received      []StatisticsPoint `json:"received"`
+	ReceivedTotal int64             `json:"received_total"`
+	Sent          []StatisticsPoint `json:"sent"`
+	SentTotal     int64             `json:"sent_total"`
+}
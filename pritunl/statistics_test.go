@@ -0,0 +1,39 @@
+package pritunl
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestServerBandwidthUnmarshal(t *testing.T) {
+	body := `{
+		"received": [[1700000000, 1024], [1700000060, 2048.5]],
+		"received_total": 3072,
+		"sent": [[1700000000, 0]],
+		"sent_total": 0
+	}`
+
+	var bandwidth ServerBandwidth
+	if err := json.Unmarshal([]byte(body), &bandwidth); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := ServerBandwidth{
+		Received:      []StatisticsPoint{{Timestamp: 1700000000, Value: 1024}, {Timestamp: 1700000060, Value: 2048.5}},
+		ReceivedTotal: 3072,
+		Sent:          []StatisticsPoint{{Timestamp: 1700000000, Value: 0}},
+	}
+	if !reflect.DeepEqual(bandwidth, expected) {
+		t.Errorf("expected %+v, got %+v", expected, bandwidth)
+	}
+}
+
+func TestStatisticsPointUnmarshalInvalid(t *testing.T) {
+	for _, body := range []string{`[1700000000]`, `[1, 2, 3]`, `{"timestamp": 1}`} {
+		var point StatisticsPoint
+		if err := json.Unmarshal([]byte(body), &point); err == nil {
+			t.Errorf("expected an error for %s, got %+v", body, point)
+		}
+	}
+}
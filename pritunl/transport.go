@@ -0,0 +1,190 @@
+package pritunl
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+type transport struct {
+	underlyingTransport http.RoundTripper
+	credentials         *credentialsCache
+	baseUrls            []string
+	userAgent           string
+	logger              Logger
+	logBodies           bool
+
+	// preferred is the index of the base URL that served the last request
+	preferred atomic.Int32
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	creds, err := t.credentials.get(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the API credentials: %w", err)
+	}
+
+	resp, err := t.failover(req, creds)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	// the credentials may have been rotated, the request is retried once with the new ones
+	rotated, err := t.credentials.get(&creds)
+	if err != nil {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to get the API credentials: %w", err)
+	}
+	if rotated == creds || (req.Body != nil && req.GetBody == nil) {
+		return resp, nil
+	}
+	resp.Body.Close()
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		retry.Body, err = req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return t.failover(retry, rotated)
+}
+
+// failover sends the request to the base URLs in turn, starting with the one that served the
+// last request, while shouldFailover allows it
+func (t *transport) failover(req *http.Request, creds credentials) (*http.Response, error) {
+	if req.URL.Host != "" {
+		return t.send(req, creds)
+	}
+
+	start := int(t.preferred.Load())
+	for i := range t.baseUrls {
+		index := (start + i) % len(t.baseUrls)
+		baseUrl := t.baseUrls[index]
+
+		attempt, err := endpointRequest(req, baseUrl, i > 0)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := t.send(attempt, creds)
+		if err == nil && resp.StatusCode < 500 {
+			t.preferred.Store(int32(index))
+			return resp, nil
+		}
+
+		// the body can't be sent again without GetBody
+		last := i == len(t.baseUrls)-1 || req.Context().Err() != nil || (req.Body != nil && req.GetBody == nil) ||
+			!shouldFailover(req.Method, resp, err)
+		if last {
+			return resp, err
+		}
+
+		t.debug(req, "Failing over to the next API URL", map[string]interface{}{
+			"method":   req.Method,
+			"path":     req.URL.Path,
+			"endpoint": baseUrl,
+		})
+		if resp != nil {
+			resp.Body.Close()
+		}
+	}
+
+	return nil, errors.New("no API URL")
+}
+
+// shouldFailover reports if the request can be sent to the next base URL. GET and HEAD
+// requests fail over on connection errors and on the 502, 503 and 504 statuses of a host
+// being patched or restarted. The other requests may have changed something already, they
+// only fail over when the connection couldn't be opened, before anything was sent.
+func shouldFailover(method string, resp *http.Response, err error) bool {
+	idempotent := method == http.MethodGet || method == http.MethodHead
+
+	if err != nil {
+		var opErr *net.OpError
+		return idempotent || (errors.As(err, &opErr) && opErr.Op == "dial")
+	}
+
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return idempotent
+	}
+	return false
+}
+
+// endpointRequest returns a copy of the request to the base URL, with a new body when rewind is set
+func endpointRequest(req *http.Request, baseUrl string, rewind bool) (*http.Request, error) {
+	u, err := url.Parse(baseUrl)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path.Join(u.Path, req.URL.Path)
+
+	attempt := req.Clone(req.Context())
+	attempt.URL = u
+
+	if rewind && req.GetBody != nil {
+		attempt.Body, err = req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return attempt, nil
+}
+
+// send signs the request with the credentials and sends it
+func (t *transport) send(req *http.Request, creds credentials) (*http.Response, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	timestampNano := strconv.FormatInt(time.Now().UnixNano(), 10)
+
+	nonceMac := hmac.New(md5.New, []byte(creds.apiSecret))
+	nonceMac.Write([]byte(strings.Join([]string{timestampNano, req.URL.Path, creds.apiToken}, "")))
+	nonce := fmt.Sprintf("%x", nonceMac.Sum(nil))
+	authString := strings.Join([]string{creds.apiToken, timestamp, nonce, strings.ToUpper(req.Method), req.URL.Path}, "&")
+
+	mac := hmac.New(sha256.New, []byte(creds.apiSecret))
+	mac.Write([]byte(authString))
+	signature := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	req.Header.Set("Auth-Token", creds.apiToken)
+	req.Header.Set("Auth-Timestamp", timestamp)
+	req.Header.Set("Auth-Nonce", nonce)
+	req.Header.Set("Auth-Signature", signature)
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", t.userAgent)
+
+	if t.logger == nil {
+		return t.underlyingTransport.RoundTrip(req)
+	}
+
+	t.logRequest(req)
+
+	start := time.Now()
+	resp, err := t.underlyingTransport.RoundTrip(req)
+	if err != nil {
+		t.debug(req, "HTTP request failed", map[string]interface{}{
+			"method":     req.Method,
+			"path":       req.URL.Path,
+			"endpoint":   req.URL.Scheme + "://" + req.URL.Host,
+			"latency_ms": time.Since(start).Milliseconds(),
+			"error":      err.Error(),
+		})
+		return nil, err
+	}
+
+	return t.logResponse(req, resp, time.Since(start))
+}
@@ -30,6 +30,19 @@ type User struct {
 	DeviceAuth      bool                     `json:"device_auth,omitempty"`
 	Organization    string                   `json:"organization,omitempty"`
 	Pin             *Pin                      `json:"pin,omitempty"`
+
+	// JSON names of the attributes sent even when they hold a zero value, e.g. to clear them on update
+	ForceSendFields []string `json:"-"`
+}
+
+func (u *User) MarshalJSON() ([]byte, error) {
+	type Alias User
+	data, err := json.Marshal((*Alias)(u))
+	if err != nil {
+		return nil, err
+	}
+
+	return addForceSendFields(data, u, u.ForceSendFields)
 }
 
 type PortForwarding struct {
@@ -46,12 +59,16 @@ type Pin struct {
 // MarshalJSON customizes the JSON encoding of the Pin struct.
 //
 // When marshaling a User JSON, the "pin" field will contain the PIN secret
-// if it is set, otherwise the field is excluded. This is used when making
-// a user create or update request to the Pritunl API.
+// if it is set, true to keep the PIN of a user read from the API, otherwise
+// null, which clears the PIN. This is used when making a user create or
+// update request to the Pritunl API.
 func (p *Pin) MarshalJSON() ([]byte, error) {
 	if p.Secret != "" {
 		return json.Marshal(p.Secret)
 	}
+	if p.IsSet {
+		return json.Marshal(true)
+	}
 	return json.Marshal(nil)
 }
 